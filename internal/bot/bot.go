@@ -1,6 +1,7 @@
 package bot
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -8,11 +9,14 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/marshall/zero-ops-bot/internal/commands"
 	"github.com/marshall/zero-ops-bot/internal/config"
+	"github.com/marshall/zero-ops-bot/internal/feedback"
 	"github.com/marshall/zero-ops-bot/internal/handlers"
 	"github.com/marshall/zero-ops-bot/internal/metadata"
 	"github.com/marshall/zero-ops-bot/internal/notes"
+	"github.com/marshall/zero-ops-bot/internal/reminders"
 	"github.com/marshall/zero-ops-bot/internal/scheduler"
 	"github.com/marshall/zero-ops-bot/internal/services"
+	"github.com/marshall/zero-ops-bot/internal/state"
 )
 
 const shutdownTimeout = 10 * time.Second
@@ -22,7 +26,9 @@ type Bot struct {
 	config    *config.Config
 	n8nClient *services.N8nClient
 	scheduler *scheduler.Scheduler
+	reminders *reminders.Manager
 	notes     *notes.Store
+	feedback  *feedback.Store
 }
 
 func New(cfg *config.Config) (*Bot, error) {
@@ -33,6 +39,8 @@ func New(cfg *config.Config) (*Bot, error) {
 
 	session.Identify.Intents = discordgo.IntentsGuilds |
 		discordgo.IntentsGuildMessages |
+		discordgo.IntentsGuildMessageReactions |
+		discordgo.IntentsDirectMessages |
 		discordgo.IntentMessageContent
 
 	return &Bot{
@@ -48,22 +56,103 @@ func (b *Bot) Start() error {
 
 	b.n8nClient = services.NewN8nClient(b.config.N8nWebhookURL, b.config.N8nWebhookSecret)
 
-	noteStore, err := notes.NewStore(b.config.NotesDir)
+	noteStore, err := notes.NewStore(b.config.NotesDir, b.config.Location)
 	if err != nil {
 		return fmt.Errorf("init notes: %w", err)
 	}
+	if b.config.NotesGit {
+		if err := noteStore.EnableHistory(); err != nil {
+			return fmt.Errorf("init note history: %w", err)
+		}
+	}
+	if migrated, err := noteStore.Migrate(); err != nil {
+		return fmt.Errorf("migrate notes: %w", err)
+	} else if migrated > 0 {
+		log.Printf("Assigned IDs to %d existing notes", migrated)
+	}
 	b.notes = noteStore
 
-	b.scheduler = scheduler.New(b.session, b.n8nClient, b.notes, b.config.Timezone)
+	if b.config.EmbeddingsURL != "" {
+		embedder := services.NewEmbeddingsClient(b.config.EmbeddingsURL, b.config.EmbeddingsModel, b.config.EmbeddingsAPIKey)
+		if err := noteStore.EnableSemantic(embedder); err != nil {
+			return fmt.Errorf("init semantic search: %w", err)
+		}
+		// Catch up on notes written while the bot was down so the first
+		// semantic search doesn't have to.
+		go func() {
+			embedded, err := noteStore.SyncEmbeddings(context.Background())
+			if err != nil {
+				log.Printf("Failed to embed notes: %v", err)
+			}
+			if embedded > 0 {
+				log.Printf("Embedded %d notes", embedded)
+			}
+		}()
+	}
+
+	feedbackStore, err := feedback.NewStore(b.config.DataDir)
+	if err != nil {
+		return fmt.Errorf("init feedback: %w", err)
+	}
+	b.feedback = feedbackStore
+
+	if err := state.LoadContexts(b.config.DataDir); err != nil {
+		return fmt.Errorf("load thread contexts: %w", err)
+	}
+	if err := state.LoadSettings(b.config.DataDir); err != nil {
+		return fmt.Errorf("load user settings: %w", err)
+	}
+
+	b.scheduler = scheduler.New(b.session, b.n8nClient, b.notes, b.config.Location)
+
+	reminderManager, err := reminders.New(b.session, b.config.DataDir, b.config.Location)
+	if err != nil {
+		return fmt.Errorf("init reminders: %w", err)
+	}
+	b.reminders = reminderManager
 
 	noteHandler := commands.NewNoteHandler(b.notes)
 	scheduleHandler := commands.NewScheduleHandler(b.scheduler)
 
+	pipeline := handlers.NewPipeline(b.n8nClient, b.notes, b.reminders, b.feedback, b.config.ForwardFeedback)
+
 	b.session.AddHandler(handlers.NewInteractionHandler(handlers.InteractionHandlers{
 		Note:     noteHandler,
 		Schedule: scheduleHandler,
+		SaveNote: commands.NewSaveNoteHandler(),
+		AskBot:   pipeline.HandleAskBotCommand,
+		Ask:      pipeline.HandleAskCommand,
+		Feedback: commands.NewFeedbackHandler(b.feedback),
+		Thread:   pipeline.HandleThreadCommand,
+		Todo:     commands.NewTodoHandler(b.notes),
+		Remind:   commands.NewRemindHandler(b.reminders),
+		Session:  commands.HandleSessionCommand,
+		Context:  commands.HandleContextCommand,
+		Settings: commands.NewSettingsHandler(b.config.Location),
+		Components: map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
+			"approval":   pipeline.HandleApproval,
+			"clarify":    pipeline.HandleClarification,
+			"note":       pipeline.HandleNoteConfirmation,
+			"notecat":    commands.NewNoteCategoryConfirmHandler(b.notes),
+			"notesearch": commands.NewNoteSearchPageHandler(b.notes),
+			"notelist":   commands.NewNoteListPageHandler(b.notes),
+			"noterm":     commands.NewNoteRemoveConfirmHandler(b.notes),
+			"remind":     commands.NewReminderButtonHandler(b.reminders),
+			"feedback":   pipeline.HandleFeedback,
+		},
+		Modals: map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
+			"savenote": commands.NewSaveNoteModalHandler(b.notes),
+		},
 	}))
-	b.session.AddHandler(handlers.NewMentionHandler(b.n8nClient, b.notes))
+	b.session.AddHandler(handlers.NewMentionHandler(pipeline, b.config.DMAllowedUsers))
+	b.session.AddHandler(handlers.NewMessageEditHandler(pipeline))
+	b.session.AddHandler(handlers.NewReactionHandler(pipeline))
+	if b.config.SummarizeOnArchive {
+		b.session.AddHandler(handlers.NewThreadArchiveHandler(pipeline))
+	}
+	if b.config.DeleteReplies {
+		b.session.AddHandler(handlers.NewMessageDeleteHandler())
+	}
 
 	b.session.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
 		log.Printf("Logged in as %s", r.User.String())
@@ -84,6 +173,7 @@ func (b *Bot) Start() error {
 		}
 	}
 	b.scheduler.Start()
+	b.reminders.Start()
 
 	return nil
 }
@@ -106,6 +196,10 @@ func (b *Bot) Stop() error {
 		b.scheduler.Stop()
 	}
 
+	if b.reminders != nil {
+		b.reminders.Stop()
+	}
+
 	if b.n8nClient != nil {
 		b.n8nClient.Shutdown(nil)
 	}
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/metadata"
+)
+
+// AskCommand builds /ask with the workflow choices taken from the catalog,
+// so it must be called after metadata is loaded.
+func AskCommand() *discordgo.ApplicationCommand {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, wf := range metadata.Get().Catalog() {
+		if wf.Name == "reject" || len(choices) == 25 {
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  wf.Name,
+			Value: wf.Name,
+		})
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:        "ask",
+		Description: "Ask the bot without starting a thread",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "prompt",
+				Description: "What to ask",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Required:    true,
+			},
+			{
+				Name:        "workflow",
+				Description: "Run this workflow directly instead of routing",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Required:    false,
+				Choices:     choices,
+			},
+			{
+				Name:        "private",
+				Description: "Only show the answer to you",
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Required:    false,
+			},
+		},
+	}
+}
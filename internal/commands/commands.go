@@ -1,11 +1,82 @@
 package commands
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/state"
+)
 
 func GetDefinitions() []*discordgo.ApplicationCommand {
 	return []*discordgo.ApplicationCommand{
 		RepoCommand,
 		NoteCommand,
+		TodoCommand,
+		RemindCommand,
 		ScheduleCommand,
+		AskCommand(),
+		FeedbackCommand,
+		ThreadCommand,
+		SessionCommand,
+		ContextCommand,
+		SettingsCommand,
+		SaveNoteCommand,
+		AskBotCommand,
+	}
+}
+
+// Respond answers an interaction with a message everyone can see.
+func Respond(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+		},
+	})
+}
+
+// RespondEphemeral answers an interaction with a message only the invoking
+// user sees.
+func RespondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// UpdateMessage replaces the message a button was attached to and removes
+// its components so it can't be clicked again.
+func UpdateMessage(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+// InteractionUser returns the invoking user for both guild and DM interactions.
+func InteractionUser(i *discordgo.InteractionCreate) *discordgo.User {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User
+	}
+	return i.User
+}
+
+// userLocation is the invoking user's own timezone, or nil for the bot's.
+func userLocation(i *discordgo.InteractionCreate) *time.Location {
+	return state.UserLocation(InteractionUser(i).ID)
+}
+
+// Truncate shortens s to at most n runes, ending in "…" when it was cut.
+func Truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
 	}
+	return string(runes[:n-1]) + "…"
 }
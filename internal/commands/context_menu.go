@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/notes"
+)
+
+const (
+	SaveNoteCommandName = "Save as note"
+	AskBotCommandName   = "Ask bot about this"
+)
+
+var SaveNoteCommand = &discordgo.ApplicationCommand{
+	Name: SaveNoteCommandName,
+	Type: discordgo.MessageApplicationCommand,
+}
+
+var AskBotCommand = &discordgo.ApplicationCommand{
+	Name: AskBotCommandName,
+	Type: discordgo.MessageApplicationCommand,
+}
+
+// NewSaveNoteHandler opens a modal pre-filled with the target message.
+func NewSaveNoteHandler() func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		data := i.ApplicationCommandData()
+		msg, ok := data.Resolved.Messages[data.TargetID]
+		if !ok {
+			Respond(s, i, "Couldn't find that message")
+			return
+		}
+
+		text := msg.Content
+		if text == "" {
+			for _, e := range msg.Embeds {
+				text = strings.TrimSpace(text + "\n" + e.Title + "\n" + e.Description)
+			}
+		}
+
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseModal,
+			Data: &discordgo.InteractionResponseData{
+				CustomID: "savenote:" + msg.ChannelID + ":" + msg.ID,
+				Title:    "Save as note",
+				Components: []discordgo.MessageComponent{
+					discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:  "text",
+							Label:     "Note",
+							Style:     discordgo.TextInputParagraph,
+							Value:     Truncate(text, 4000),
+							Required:  true,
+							MaxLength: 4000,
+						},
+					}},
+					discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "category",
+							Label:       "Category",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "daily",
+							Required:    false,
+							MaxLength:   50,
+						},
+					}},
+				},
+			},
+		})
+	}
+}
+
+// NewSaveNoteModalHandler saves the submitted modal with a link back to the
+// original message.
+func NewSaveNoteModalHandler(store *notes.Store) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		data := i.ModalSubmitData()
+		parts := strings.Split(data.CustomID, ":")
+		if len(parts) != 3 {
+			return
+		}
+
+		var text, category string
+		for _, row := range data.Components {
+			for _, c := range row.(*discordgo.ActionsRow).Components {
+				input := c.(*discordgo.TextInput)
+				switch input.CustomID {
+				case "text":
+					text = strings.Join(strings.Fields(input.Value), " ")
+				case "category":
+					category = strings.TrimSpace(input.Value)
+				}
+			}
+		}
+
+		guildID := i.GuildID
+		if guildID == "" {
+			guildID = "@me"
+		}
+		link := fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, parts[1], parts[2])
+
+		Respond(s, i, NoteAddMessage(store, notes.Note{
+			Text:     fmt.Sprintf("%s (%s)", text, link),
+			Category: category,
+			Author:   InteractionUser(i).Username,
+		}, userLocation(i)))
+	}
+}
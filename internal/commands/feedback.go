@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"bytes"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/feedback"
+)
+
+var feedbackPermissions int64 = discordgo.PermissionManageServer
+
+var FeedbackCommand = &discordgo.ApplicationCommand{
+	Name:                     "feedback",
+	Description:              "Answer feedback dataset",
+	DefaultMemberPermissions: &feedbackPermissions,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "export",
+			Description: "Download all feedback as JSONL",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+		},
+	},
+}
+
+func NewFeedbackHandler(store *feedback.Store) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		options := i.ApplicationCommandData().Options
+		if len(options) == 0 {
+			Respond(s, i, "No subcommand provided")
+			return
+		}
+
+		switch options[0].Name {
+		case "export":
+			handleFeedbackExport(s, i, store)
+		}
+	}
+}
+
+func handleFeedbackExport(s *discordgo.Session, i *discordgo.InteractionCreate, store *feedback.Store) {
+	data, err := store.Export()
+	if err != nil {
+		Respond(s, i, "Failed to export feedback: "+err.Error())
+		return
+	}
+	if len(data) == 0 {
+		Respond(s, i, "No feedback recorded yet")
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "Feedback dataset:",
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Files: []*discordgo.File{
+				{
+					Name:        "feedback.jsonl",
+					ContentType: "application/jsonl",
+					Reader:      bytes.NewReader(data),
+				},
+			},
+		},
+	})
+}
@@ -2,10 +2,12 @@ package commands
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/dates"
 	"github.com/marshall/zero-ops-bot/internal/notes"
 )
 
@@ -30,6 +32,12 @@ var NoteCommand = &discordgo.ApplicationCommand{
 					Type:        discordgo.ApplicationCommandOptionString,
 					Required:    false,
 				},
+				{
+					Name:        "tags",
+					Description: "Comma-separated tags",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
 			},
 		},
 		{
@@ -39,55 +47,71 @@ var NoteCommand = &discordgo.ApplicationCommand{
 		},
 		{
 			Name:        "list",
-			Description: "List notes by date or category",
+			Description: "List notes by day, range or category",
 			Type:        discordgo.ApplicationCommandOptionSubCommand,
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Name:        "date",
-					Description: "Date in YYYY-MM-DD format",
-					Type:        discordgo.ApplicationCommandOptionString,
-					Required:    false,
-				},
+			Options: append(slices.Clone(rangeOptions), &discordgo.ApplicationCommandOption{
+				Name:        "category",
+				Description: "Category name",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Required:    false,
+			}),
+		},
+		{
+			Name:        "remove",
+			Description: "Remove a note by ID, or every daily note of a day or range",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: append([]*discordgo.ApplicationCommandOption{
 				{
-					Name:        "category",
-					Description: "Category name",
+					Name:        "id",
+					Description: "Note ID, as shown by list, today or search",
 					Type:        discordgo.ApplicationCommandOptionString,
 					Required:    false,
 				},
-			},
+			}, rangeOptions...),
 		},
 		{
-			Name:        "remove",
-			Description: "Remove a note by index",
+			Name:        "edit",
+			Description: "Replace the text of a note",
 			Type:        discordgo.ApplicationCommandOptionSubCommand,
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Name:        "index",
-					Description: "Note number to remove",
-					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "id",
+					Description: "Note ID",
+					Type:        discordgo.ApplicationCommandOptionString,
 					Required:    true,
 				},
 				{
-					Name:        "date",
-					Description: "Date in YYYY-MM-DD format (default: today)",
+					Name:        "text",
+					Description: "New note content",
 					Type:        discordgo.ApplicationCommandOptionString,
-					Required:    false,
+					Required:    true,
 				},
 			},
 		},
 		{
-			Name:        "search",
-			Description: "Search across all notes",
+			Name:        "move",
+			Description: "Move a note to another category or back to its daily file",
 			Type:        discordgo.ApplicationCommandOptionSubCommand,
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Name:        "query",
-					Description: "Search query",
+					Name:        "id",
+					Description: "Note ID",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+				{
+					Name:        "category",
+					Description: "Target category (\"daily\" for the note's daily file)",
 					Type:        discordgo.ApplicationCommandOptionString,
 					Required:    true,
 				},
 			},
 		},
+		NoteCategoryCommand,
+		NoteSearchCommand,
+		NoteHistoryCommand,
+		NoteUndoCommand,
+		NoteExportCommand,
 	},
 }
 
@@ -95,7 +119,7 @@ func NewNoteHandler(store *notes.Store) func(s *discordgo.Session, i *discordgo.
 	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		options := i.ApplicationCommandData().Options
 		if len(options) == 0 {
-			respond(s, i, "No subcommand provided")
+			Respond(s, i, "No subcommand provided")
 			return
 		}
 
@@ -108,129 +132,227 @@ func NewNoteHandler(store *notes.Store) func(s *discordgo.Session, i *discordgo.
 			handleNoteList(s, i, options[0].Options, store)
 		case "remove":
 			handleNoteRemove(s, i, options[0].Options, store)
+		case "edit":
+			handleNoteEdit(s, i, options[0].Options, store)
+		case "move":
+			handleNoteMove(s, i, options[0].Options, store)
+		case "category":
+			handleNoteCategory(s, i, options[0].Options, store)
 		case "search":
 			handleNoteSearch(s, i, options[0].Options, store)
+		case "history":
+			handleNoteHistory(s, i, options[0].Options, store)
+		case "undo":
+			handleNoteUndo(s, i, store)
+		case "export":
+			handleNoteExport(s, i, options[0].Options, store)
 		}
 	}
 }
 
 func handleNoteAdd(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, store *notes.Store) {
-	var text, category string
+	note := notes.Note{Author: InteractionUser(i).Username}
 	for _, opt := range opts {
 		switch opt.Name {
 		case "text":
-			text = opt.StringValue()
+			note.Text = opt.StringValue()
 		case "category":
-			category = opt.StringValue()
+			note.Category = opt.StringValue()
+		case "tags":
+			note.Tags = notes.ParseTags(opt.StringValue())
 		}
 	}
 
-	if err := store.Add(text, category); err != nil {
-		respond(s, i, "Failed to add note: "+err.Error())
-		return
-	}
+	Respond(s, i, NoteAddMessage(store, note, userLocation(i)))
+}
 
-	label := "daily"
+func handleNoteToday(s *discordgo.Session, i *discordgo.InteractionCreate, store *notes.Store) {
+	Respond(s, i, NoteTodayMessage(store, userLocation(i)))
+}
+
+func handleNoteList(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, store *notes.Store) {
+	var category string
+	for _, opt := range opts {
+		if opt.Name == "category" {
+			category = opt.StringValue()
+		}
+	}
 	if category != "" {
-		label = category
+		Respond(s, i, NoteListMessage(store, "", category, userLocation(i)))
+		return
 	}
-	respond(s, i, fmt.Sprintf("Noted in **%s**: %s", label, text))
-}
 
-func handleNoteToday(s *discordgo.Session, i *discordgo.InteractionCreate, store *notes.Store) {
-	content, err := store.GetToday()
+	date, from, to := rangeOptionValues(opts)
+	r, err := resolveRange(date, from, to, store.Now(userLocation(i)))
 	if err != nil {
-		respond(s, i, "Failed to read notes: "+err.Error())
+		RespondEphemeral(s, i, err.Error())
 		return
 	}
-	if content == "" {
-		respond(s, i, "No notes for today")
+	if r.Single() {
+		Respond(s, i, NoteListMessage(store, r.From.Format(dates.Layout), "", userLocation(i)))
 		return
 	}
-	respond(s, i, content)
+	respondRange(s, i, store, r)
 }
 
-func handleNoteList(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, store *notes.Store) {
-	var date, category string
+func handleNoteRemove(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, store *notes.Store) {
+	var id string
 	for _, opt := range opts {
-		switch opt.Name {
-		case "date":
-			date = opt.StringValue()
-		case "category":
-			category = opt.StringValue()
+		if opt.Name == "id" {
+			id = opt.StringValue()
 		}
 	}
+	date, from, to := rangeOptionValues(opts)
 
-	if category != "" {
-		content, err := store.GetByCategory(category)
+	switch {
+	case id != "" && date+from+to != "":
+		RespondEphemeral(s, i, "Give either a note ID or dates, not both.")
+	case id != "":
+		Respond(s, i, NoteRemoveMessage(store, id, InteractionUser(i).Username))
+	case date+from+to == "":
+		RespondEphemeral(s, i, "Give a note ID, or a date or range to remove whole days.")
+	default:
+		r, err := resolveRange(date, from, to, store.Now(userLocation(i)))
 		if err != nil {
-			respond(s, i, "Failed to read category: "+err.Error())
+			RespondEphemeral(s, i, err.Error())
 			return
 		}
-		if content == "" {
-			respond(s, i, fmt.Sprintf("No notes in category **%s**", category))
-			return
+		handleNoteRemoveDays(s, i, store, r)
+	}
+}
+
+func handleNoteEdit(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, store *notes.Store) {
+	var id, text string
+	for _, opt := range opts {
+		switch opt.Name {
+		case "id":
+			id = opt.StringValue()
+		case "text":
+			text = opt.StringValue()
 		}
-		respond(s, i, content)
-		return
 	}
 
-	if date == "" {
-		date = time.Now().Format("2006-01-02")
+	Respond(s, i, NoteEditMessage(store, id, text, InteractionUser(i).Username))
+}
+
+func handleNoteMove(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, store *notes.Store) {
+	var id, category string
+	for _, opt := range opts {
+		switch opt.Name {
+		case "id":
+			id = opt.StringValue()
+		case "category":
+			category = opt.StringValue()
+		}
 	}
 
-	content, err := store.GetByDate(date)
+	Respond(s, i, NoteMoveMessage(store, id, category, InteractionUser(i).Username))
+}
+
+// The Note*Message functions perform a note operation and return the reply
+// text. They are shared by the slash commands and the mention router so both
+// answer the same way. A *time.Location argument is the user's timezone, nil
+// for the bot's.
+
+func NoteAddMessage(store *notes.Store, note notes.Note, loc *time.Location) string {
+	saved, err := store.Add(note, loc)
 	if err != nil {
-		respond(s, i, "Failed to read notes: "+err.Error())
-		return
+		return "Failed to add note: " + err.Error()
 	}
-	if content == "" {
-		respond(s, i, fmt.Sprintf("No notes for %s", date))
-		return
+
+	label := "daily"
+	if saved.Category != "" {
+		label = saved.Category
 	}
-	respond(s, i, content)
+	return fmt.Sprintf("Noted in **%s** (`%s`): %s", label, saved.ID, saved.Text)
 }
 
-func handleNoteRemove(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, store *notes.Store) {
-	var index int64
-	date := time.Now().Format("2006-01-02")
+func NoteTodayMessage(store *notes.Store, loc *time.Location) string {
+	date := store.Today(loc)
+	list, err := store.Daily(date)
+	if err != nil {
+		return "Failed to read notes: " + err.Error()
+	}
+	if len(list) == 0 {
+		return "No notes for today"
+	}
+	return formatNotes("Notes for "+date, list)
+}
 
-	for _, opt := range opts {
-		switch opt.Name {
-		case "index":
-			index = opt.IntValue()
-		case "date":
-			date = opt.StringValue()
+// NoteListMessage lists a category, or the daily notes of date: a day or a
+// range in the dates package's syntax, today when empty. Ranges show their
+// first page only.
+func NoteListMessage(store *notes.Store, date, category string, loc *time.Location) string {
+	if category != "" {
+		list, err := store.Category(category)
+		if err != nil {
+			return "Failed to read category: " + err.Error()
+		}
+		if len(list) == 0 {
+			return fmt.Sprintf("No notes in category **%s**", category)
 		}
+		return formatNotes("Notes in "+category, list)
 	}
 
-	if err := store.Remove(date, int(index)); err != nil {
-		respond(s, i, "Failed to remove note: "+err.Error())
-		return
+	if date == "" {
+		date = "today"
+	}
+	r, err := dates.ParseRange(date, store.Now(loc))
+	if err != nil {
+		return err.Error()
 	}
+	if !r.Single() {
+		content, pages := rangePage(store, r, 0)
+		if pages > 1 {
+			content += "\n-# Use `/note list` to see every page."
+		}
+		return content
+	}
+	date = r.From.Format(dates.Layout)
 
-	respond(s, i, fmt.Sprintf("Removed note #%d from %s", index, date))
+	list, err := store.Daily(date)
+	if err != nil {
+		return "Failed to read notes: " + err.Error()
+	}
+	if len(list) == 0 {
+		return fmt.Sprintf("No notes for %s", date)
+	}
+	return formatNotes("Notes for "+date, list)
 }
 
-func handleNoteSearch(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, store *notes.Store) {
-	query := opts[0].StringValue()
+func NoteRemoveMessage(store *notes.Store, id, by string) string {
+	note, err := store.Remove(id, by)
+	if err != nil {
+		return "Failed to remove note: " + err.Error()
+	}
+	return fmt.Sprintf("Removed note `%s` from %s: %s", note.ID, note.Source(), note.Text)
+}
 
-	results, err := store.Search(query)
+func NoteEditMessage(store *notes.Store, id, text, by string) string {
+	note, err := store.Edit(id, text, by)
 	if err != nil {
-		respond(s, i, "Search failed: "+err.Error())
-		return
+		return "Failed to edit note: " + err.Error()
 	}
+	return fmt.Sprintf("Updated note `%s` in %s: %s", note.ID, note.Source(), note.Text)
+}
 
-	if len(results) == 0 {
-		respond(s, i, fmt.Sprintf("No notes matching **%s**", query))
-		return
+func NoteMoveMessage(store *notes.Store, id, category, by string) string {
+	note, err := store.Move(id, category, by)
+	if err != nil {
+		return "Failed to move note: " + err.Error()
 	}
+	target := "daily"
+	if note.Category != "" {
+		target = note.Category
+	}
+	return fmt.Sprintf("Moved note `%s` to **%s**: %s", note.ID, target, note.Text)
+}
 
+func formatNotes(title string, list []notes.Note) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("**Search results for \"%s\":**\n", query))
-	for _, r := range results {
-		sb.WriteString(r + "\n")
+	sb.WriteString("**" + title + ":**\n")
+	for _, n := range list {
+		sb.WriteString(n.String() + "\n")
 	}
-
-	respond(s, i, sb.String())
+	return sb.String()
 }
@@ -0,0 +1,190 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/notes"
+	"github.com/marshall/zero-ops-bot/internal/state"
+)
+
+var NoteCategoryCommand = &discordgo.ApplicationCommandOption{
+	Name:        "category",
+	Description: "Manage note categories",
+	Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "rename",
+			Description: "Rename a category",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "from",
+					Description: "Current category name",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+				{
+					Name:        "to",
+					Description: "New category name",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "merge",
+			Description: "Move every note of a category into another and delete it",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "from",
+					Description: "Category to merge and delete",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+				{
+					Name:        "into",
+					Description: "Category that receives the notes",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "delete",
+			Description: "Delete a category and all its notes",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "name",
+					Description: "Category to delete",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+			},
+		},
+	},
+}
+
+// categoryChange is a category operation waiting for confirmation.
+type categoryChange struct {
+	userID string
+	user   string
+	op     string
+	from   string
+	to     string
+}
+
+func handleNoteCategory(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, store *notes.Store) {
+	if len(opts) == 0 {
+		Respond(s, i, "No subcommand provided")
+		return
+	}
+
+	user := InteractionUser(i)
+	change := &categoryChange{userID: user.ID, user: user.Username, op: opts[0].Name}
+	for _, opt := range opts[0].Options {
+		switch opt.Name {
+		case "from", "name":
+			change.from = opt.StringValue()
+		case "to", "into":
+			change.to = opt.StringValue()
+		}
+	}
+
+	list, err := store.Category(change.from)
+	if err != nil {
+		Respond(s, i, "Failed to read category: "+err.Error())
+		return
+	}
+	if list == nil {
+		Respond(s, i, fmt.Sprintf("Category **%s** not found", change.from))
+		return
+	}
+
+	var prompt string
+	switch change.op {
+	case "rename":
+		prompt = fmt.Sprintf("Rename **%s** (%d notes) to **%s**?", change.from, len(list), change.to)
+	case "merge":
+		prompt = fmt.Sprintf("Move all %d notes of **%s** into **%s** and delete **%s**?", len(list), change.from, change.to, change.from)
+	case "delete":
+		prompt = fmt.Sprintf("Delete **%s** and its %d notes? This can't be undone.", change.from, len(list))
+	default:
+		Respond(s, i, "Unknown subcommand")
+		return
+	}
+
+	id := state.PutPending(change)
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: prompt,
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{Label: "Confirm", Style: discordgo.DangerButton, CustomID: "notecat:" + id + ":yes"},
+						discordgo.Button{Label: "Cancel", Style: discordgo.SecondaryButton, CustomID: "notecat:" + id + ":no"},
+					},
+				},
+			},
+		},
+	})
+}
+
+// NewNoteCategoryConfirmHandler runs or cancels a confirmed category change.
+func NewNoteCategoryConfirmHandler(store *notes.Store) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		parts := strings.Split(i.MessageComponentData().CustomID, ":")
+		if len(parts) != 3 {
+			return
+		}
+
+		v, ok := state.PeekPending(parts[1])
+		if !ok {
+			UpdateMessage(s, i, "This request has expired.")
+			return
+		}
+		change := v.(*categoryChange)
+
+		if InteractionUser(i).ID != change.userID {
+			RespondEphemeral(s, i, "Only the person who asked can confirm this.")
+			return
+		}
+
+		if _, ok := state.TakePending(parts[1]); !ok {
+			return
+		}
+
+		if parts[2] != "yes" {
+			UpdateMessage(s, i, "Cancelled.")
+			return
+		}
+
+		UpdateMessage(s, i, applyCategoryChange(store, change))
+	}
+}
+
+func applyCategoryChange(store *notes.Store, change *categoryChange) string {
+	switch change.op {
+	case "rename":
+		if err := store.RenameCategory(change.from, change.to, change.user); err != nil {
+			return "Failed to rename category: " + err.Error()
+		}
+		return fmt.Sprintf("Renamed **%s** to **%s**", change.from, change.to)
+	case "merge":
+		moved, err := store.MergeCategory(change.from, change.to, change.user)
+		if err != nil {
+			return "Failed to merge category: " + err.Error()
+		}
+		return fmt.Sprintf("Moved %d notes from **%s** into **%s**", moved, change.from, change.to)
+	default:
+		deleted, err := store.DeleteCategory(change.from, change.user)
+		if err != nil {
+			return "Failed to delete category: " + err.Error()
+		}
+		return fmt.Sprintf("Deleted **%s** (%d notes)", change.from, deleted)
+	}
+}
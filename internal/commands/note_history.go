@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/notes"
+)
+
+const (
+	defaultHistoryCount = 10
+	maxHistoryCount     = 25
+)
+
+var minHistoryCount = 1.0
+
+var NoteHistoryCommand = &discordgo.ApplicationCommandOption{
+	Name:        "history",
+	Description: "Show recent changes to the notes",
+	Type:        discordgo.ApplicationCommandOptionSubCommand,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "count",
+			Description: fmt.Sprintf("How many changes to show (default %d)", defaultHistoryCount),
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Required:    false,
+			MinValue:    &minHistoryCount,
+			MaxValue:    maxHistoryCount,
+		},
+	},
+}
+
+var NoteUndoCommand = &discordgo.ApplicationCommandOption{
+	Name:        "undo",
+	Description: "Revert the last change to the notes",
+	Type:        discordgo.ApplicationCommandOptionSubCommand,
+}
+
+func handleNoteHistory(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, store *notes.Store) {
+	if !store.HistoryEnabled() {
+		RespondEphemeral(s, i, "Note history is not enabled. Set `NOTES_GIT=true` to keep it.")
+		return
+	}
+
+	count := defaultHistoryCount
+	for _, opt := range opts {
+		if opt.Name == "count" {
+			count = int(opt.IntValue())
+		}
+	}
+
+	changes, err := store.History(count)
+	if err != nil {
+		Respond(s, i, "Failed to read history: "+err.Error())
+		return
+	}
+	if len(changes) == 0 {
+		Respond(s, i, "No changes yet")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("**Recent note changes:**\n")
+	for _, c := range changes {
+		sb.WriteString(fmt.Sprintf("- `%s` <t:%d:R> %s\n", c.Hash[:7], c.Time.Unix(), Truncate(c.Message, 150)))
+	}
+	Respond(s, i, sb.String())
+}
+
+func handleNoteUndo(s *discordgo.Session, i *discordgo.InteractionCreate, store *notes.Store) {
+	if !store.HistoryEnabled() {
+		RespondEphemeral(s, i, "Note history is not enabled. Set `NOTES_GIT=true` to keep it.")
+		return
+	}
+
+	change, err := store.Undo(InteractionUser(i).Username)
+	if err != nil {
+		Respond(s, i, "Failed to undo: "+err.Error())
+		return
+	}
+	Respond(s, i, fmt.Sprintf("Undid `%s`: %s", change.Hash[:7], change.Message))
+}
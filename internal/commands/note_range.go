@@ -0,0 +1,342 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/dates"
+	"github.com/marshall/zero-ops-bot/internal/notes"
+	"github.com/marshall/zero-ops-bot/internal/state"
+)
+
+// maxPageLength keeps a page of a range listing under Discord's message limit
+// with room for the header and footer.
+const maxPageLength = 1800
+
+// rangeOptions are the date options shared by /note list, remove and export.
+var rangeOptions = []*discordgo.ApplicationCommandOption{
+	{
+		Name:        "date",
+		Description: "Day or range: yesterday, last friday, -3d, this week, YYYY-MM-DD",
+		Type:        discordgo.ApplicationCommandOptionString,
+		Required:    false,
+	},
+	{
+		Name:        "from",
+		Description: "Start of a range, in the same formats as date",
+		Type:        discordgo.ApplicationCommandOptionString,
+		Required:    false,
+	},
+	{
+		Name:        "to",
+		Description: "End of a range (default: today)",
+		Type:        discordgo.ApplicationCommandOptionString,
+		Required:    false,
+	},
+}
+
+var NoteExportCommand = &discordgo.ApplicationCommandOption{
+	Name:        "export",
+	Description: "Download daily notes as a markdown file",
+	Type:        discordgo.ApplicationCommandOptionSubCommand,
+	Options:     rangeOptions,
+}
+
+// noteRangeRemoval is a /note remove of whole days waiting for confirmation.
+type noteRangeRemoval struct {
+	userID string
+	user   string
+	days   dates.Range
+}
+
+// resolveRange reads the date, from and to options; with none of them it is
+// today. A lone to is a single day.
+func resolveRange(date, from, to string, now time.Time) (dates.Range, error) {
+	switch {
+	case from != "" || to != "":
+		if date != "" {
+			return dates.Range{}, fmt.Errorf("use either date or from/to, not both")
+		}
+		if from == "" {
+			from = to
+		}
+		return dates.Between(from, to, now)
+	case date != "":
+		return dates.ParseRange(date, now)
+	default:
+		return dates.ParseRange("today", now)
+	}
+}
+
+func rangeOptionValues(opts []*discordgo.ApplicationCommandInteractionDataOption) (date, from, to string) {
+	for _, opt := range opts {
+		switch opt.Name {
+		case "date":
+			date = opt.StringValue()
+		case "from":
+			from = opt.StringValue()
+		case "to":
+			to = opt.StringValue()
+		}
+	}
+	return date, from, to
+}
+
+// rangePages renders the daily notes of r, a section per day with notes,
+// split into pages.
+func rangePages(store *notes.Store, r dates.Range) ([]string, int, error) {
+	var sections []string
+	total := 0
+	for _, day := range r.Days() {
+		list, err := store.Daily(day)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(list) == 0 {
+			continue
+		}
+		total += len(list)
+		sections = append(sections, formatNotes(day, list))
+	}
+
+	var pages []string
+	var page strings.Builder
+	for _, section := range sections {
+		for _, line := range strings.SplitAfter(strings.TrimSuffix(section, "\n"), "\n") {
+			if page.Len() > 0 && page.Len()+len(line) > maxPageLength {
+				pages = append(pages, page.String())
+				page.Reset()
+			}
+			page.WriteString(Truncate(line, maxPageLength))
+		}
+		page.WriteString("\n\n")
+	}
+	if page.Len() > 0 {
+		pages = append(pages, page.String())
+	}
+	return pages, total, nil
+}
+
+// rangePage renders one page of a range listing.
+func rangePage(store *notes.Store, r dates.Range, page int) (string, int) {
+	pages, total, err := rangePages(store, r)
+	if err != nil {
+		return "Failed to read notes: " + err.Error(), 0
+	}
+	if total == 0 {
+		return fmt.Sprintf("No notes for %s", r), 0
+	}
+
+	page = max(0, min(page, len(pages)-1))
+	content := fmt.Sprintf("**Notes for %s** (%d):\n\n%s", r, total, strings.TrimRight(pages[page], "\n"))
+	if len(pages) > 1 {
+		content += fmt.Sprintf("\n-# Page %d of %d", page+1, len(pages))
+	}
+	return content, len(pages)
+}
+
+func respondRange(s *discordgo.Session, i *discordgo.InteractionCreate, store *notes.Store, r dates.Range) {
+	content, pages := rangePage(store, r, 0)
+	var components []discordgo.MessageComponent
+	if pages > 1 {
+		components = pagerButtons("notelist", state.PutPending(r), 0, pages)
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: components,
+		},
+	})
+}
+
+// NewNoteListPageHandler turns the pages of a range listing:
+// "notelist:<id>:<page>".
+func NewNoteListPageHandler(store *notes.Store) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		parts := strings.Split(i.MessageComponentData().CustomID, ":")
+		if len(parts) != 3 {
+			return
+		}
+
+		v, ok := state.PeekPending(parts[1])
+		if !ok {
+			UpdateMessage(s, i, i.Message.Content+"\n-# This listing has expired, run it again.")
+			return
+		}
+
+		page, _ := strconv.Atoi(parts[2])
+		content, pages := rangePage(store, v.(dates.Range), page)
+		var components []discordgo.MessageComponent
+		if pages > 1 {
+			components = pagerButtons("notelist", parts[1], max(0, min(page, pages-1)), pages)
+		}
+
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content:    content,
+				Components: components,
+			},
+		})
+	}
+}
+
+// handleNoteRemoveDays asks before deleting every daily note in a range.
+func handleNoteRemoveDays(s *discordgo.Session, i *discordgo.InteractionCreate, store *notes.Store, r dates.Range) {
+	total := 0
+	for _, day := range r.Days() {
+		list, err := store.Daily(day)
+		if err != nil {
+			Respond(s, i, "Failed to read notes: "+err.Error())
+			return
+		}
+		total += len(list)
+	}
+	if total == 0 {
+		RespondEphemeral(s, i, fmt.Sprintf("No daily notes for %s", r))
+		return
+	}
+
+	user := InteractionUser(i)
+	id := state.PutPending(&noteRangeRemoval{userID: user.ID, user: user.Username, days: r})
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("Remove all %d daily notes from %s? Category notes are kept.", total, r),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Label:    "Remove",
+							Style:    discordgo.DangerButton,
+							CustomID: "noterm:" + id + ":yes",
+						},
+						discordgo.Button{
+							Label:    "Cancel",
+							Style:    discordgo.SecondaryButton,
+							CustomID: "noterm:" + id + ":no",
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// NewNoteRemoveConfirmHandler runs or cancels a confirmed removal of whole
+// days: "noterm:<id>:yes|no".
+func NewNoteRemoveConfirmHandler(store *notes.Store) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		parts := strings.Split(i.MessageComponentData().CustomID, ":")
+		if len(parts) != 3 {
+			return
+		}
+
+		v, ok := state.PeekPending(parts[1])
+		if !ok {
+			UpdateMessage(s, i, "This request has expired.")
+			return
+		}
+		removal := v.(*noteRangeRemoval)
+
+		if InteractionUser(i).ID != removal.userID {
+			RespondEphemeral(s, i, "Only the person who asked can confirm this.")
+			return
+		}
+
+		if _, ok := state.TakePending(parts[1]); !ok {
+			return
+		}
+
+		if parts[2] != "yes" {
+			UpdateMessage(s, i, "Cancelled.")
+			return
+		}
+
+		removed, err := store.RemoveDays(removal.days.Days(), removal.user)
+		if err != nil {
+			UpdateMessage(s, i, "Failed to remove notes: "+err.Error())
+			return
+		}
+		UpdateMessage(s, i, fmt.Sprintf("Removed %d daily notes from %s", removed, removal.days))
+	}
+}
+
+func handleNoteExport(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, store *notes.Store) {
+	date, from, to := rangeOptionValues(opts)
+	r, err := resolveRange(date, from, to, store.Now(userLocation(i)))
+	if err != nil {
+		RespondEphemeral(s, i, err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	for _, day := range r.Days() {
+		content, err := store.GetByDate(day)
+		if err != nil {
+			Respond(s, i, "Failed to export notes: "+err.Error())
+			return
+		}
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteString("\n\n")
+		}
+		buf.WriteString(strings.TrimRight(content, "\n"))
+	}
+	if buf.Len() == 0 {
+		RespondEphemeral(s, i, fmt.Sprintf("No daily notes for %s", r))
+		return
+	}
+	buf.WriteString("\n")
+
+	name := "notes-" + r.From.Format(dates.Layout)
+	if !r.Single() {
+		name += "_" + r.To.Format(dates.Layout)
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("Notes for %s:", r),
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Files: []*discordgo.File{
+				{
+					Name:        name + ".md",
+					ContentType: "text/markdown",
+					Reader:      &buf,
+				},
+			},
+		},
+	})
+}
+
+// pagerButtons are Prev/Next buttons whose custom IDs are
+// "<prefix>:<pendingID>:<page>".
+func pagerButtons(prefix, pendingID string, page, pages int) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "◀ Prev",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("%s:%s:%d", prefix, pendingID, page-1),
+					Disabled: page == 0,
+				},
+				discordgo.Button{
+					Label:    "Next ▶",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("%s:%s:%d", prefix, pendingID, page+1),
+					Disabled: page+1 >= pages,
+				},
+			},
+		},
+	}
+}
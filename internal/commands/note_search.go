@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/notes"
+	"github.com/marshall/zero-ops-bot/internal/state"
+)
+
+var NoteSearchCommand = &discordgo.ApplicationCommandOption{
+	Name:        "search",
+	Description: "Search across all notes",
+	Type:        discordgo.ApplicationCommandOptionSubCommand,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "query",
+			Description: `Words must all match; "quoted phrase", #tag, in:category, from:/to:YYYY-MM-DD`,
+			Type:        discordgo.ApplicationCommandOptionString,
+			Required:    false,
+		},
+		{
+			Name:        "category",
+			Description: "Only this category (\"daily\" for daily notes)",
+			Type:        discordgo.ApplicationCommandOptionString,
+			Required:    false,
+		},
+		{
+			Name:        "tag",
+			Description: "Only notes with this tag",
+			Type:        discordgo.ApplicationCommandOptionString,
+			Required:    false,
+		},
+		{
+			Name:        "from",
+			Description: "From date in YYYY-MM-DD format",
+			Type:        discordgo.ApplicationCommandOptionString,
+			Required:    false,
+		},
+		{
+			Name:        "to",
+			Description: "To date in YYYY-MM-DD format",
+			Type:        discordgo.ApplicationCommandOptionString,
+			Required:    false,
+		},
+		{
+			Name:        "mode",
+			Description: "Match words (default) or search by meaning",
+			Type:        discordgo.ApplicationCommandOptionString,
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "keyword", Value: "keyword"},
+				{Name: "semantic", Value: "semantic"},
+			},
+		},
+	},
+}
+
+// maxResultLine keeps a page of results under Discord's message limit.
+const maxResultLine = 170
+
+func handleNoteSearch(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, store *notes.Store) {
+	var q notes.Query
+	for _, opt := range opts {
+		switch opt.Name {
+		case "query":
+			q = notes.ParseQuery(opt.StringValue())
+		}
+	}
+	for _, opt := range opts {
+		switch opt.Name {
+		case "category":
+			q.Category = opt.StringValue()
+		case "tag":
+			q.Tags = append(q.Tags, notes.ParseTags(opt.StringValue())...)
+		case "from":
+			q.From = opt.StringValue()
+		case "to":
+			q.To = opt.StringValue()
+		case "mode":
+			q.Semantic = opt.StringValue() == "semantic"
+		}
+	}
+
+	if !q.Semantic {
+		content, result := searchPage(store, q)
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content:    content,
+				Components: searchButtons(q, result),
+			},
+		})
+		return
+	}
+
+	if !store.SemanticEnabled() {
+		RespondEphemeral(s, i, "Semantic search is not set up. Set `EMBEDDINGS_URL` and `EMBEDDINGS_MODEL` to enable it.")
+		return
+	}
+
+	// Embedding new notes and the query can take longer than Discord waits
+	// for a reply.
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	content, result := searchPage(store, q)
+	components := searchButtons(q, result)
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Components: &components,
+	})
+}
+
+func searchButtons(q notes.Query, result notes.SearchResult) []discordgo.MessageComponent {
+	if result.Pages <= 1 {
+		return nil
+	}
+	return pageButtons(state.PutPending(q), result)
+}
+
+// NoteSearchMessage returns the first page of results for a free-text query.
+// It is used by the mention router, which can't page. A semantic search falls
+// back to keywords when no embeddings endpoint is configured.
+func NoteSearchMessage(store *notes.Store, query string, semantic bool) string {
+	q := notes.ParseQuery(query)
+	q.Semantic = (q.Semantic || semantic) && store.SemanticEnabled()
+	content, result := searchPage(store, q)
+	if result.Pages > 1 {
+		content += "\n-# Use `/note search` to see every result."
+	}
+	return content
+}
+
+// NewNoteSearchPageHandler turns the pages of a search result:
+// "notesearch:<id>:<page>".
+func NewNoteSearchPageHandler(store *notes.Store) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		parts := strings.Split(i.MessageComponentData().CustomID, ":")
+		if len(parts) != 3 {
+			return
+		}
+
+		v, ok := state.PeekPending(parts[1])
+		if !ok {
+			UpdateMessage(s, i, i.Message.Content+"\n-# This search has expired, run it again.")
+			return
+		}
+
+		q := v.(notes.Query)
+		q.Page, _ = strconv.Atoi(parts[2])
+		if q.Semantic {
+			s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseDeferredMessageUpdate,
+			})
+		}
+
+		content, result := searchPage(store, q)
+		var components []discordgo.MessageComponent
+		if result.Pages > 1 {
+			components = pageButtons(parts[1], result)
+		}
+
+		if q.Semantic {
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content:    &content,
+				Components: &components,
+			})
+			return
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content:    content,
+				Components: components,
+			},
+		})
+	}
+}
+
+// searchPage runs a search and renders the requested page of results, each
+// with the file and date it came from.
+func searchPage(store *notes.Store, q notes.Query) (string, notes.SearchResult) {
+	result, err := store.Search(q)
+	if err != nil {
+		return "Search failed: " + err.Error(), result
+	}
+	if result.Total == 0 {
+		return fmt.Sprintf("No notes matching **%s**", q), result
+	}
+
+	var sb strings.Builder
+	if q.Semantic {
+		sb.WriteString(fmt.Sprintf("**Closest notes to `%s`** (%d):\n", q, result.Total))
+	} else {
+		sb.WriteString(fmt.Sprintf("**Search results for `%s`** (%d):\n", q, result.Total))
+	}
+	for _, n := range result.Notes {
+		location := "`" + n.File() + "`"
+		if n.Category != "" {
+			location += " " + n.Date
+		}
+		sb.WriteString(Truncate(n.String(), maxResultLine) + " · " + location + "\n")
+	}
+	if result.Pages > 1 {
+		sb.WriteString(fmt.Sprintf("-# Page %d of %d", result.Page+1, result.Pages))
+	}
+	return sb.String(), result
+}
+
+func pageButtons(pendingID string, result notes.SearchResult) []discordgo.MessageComponent {
+	return pagerButtons("notesearch", pendingID, result.Page, result.Pages)
+}
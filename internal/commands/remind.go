@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/dates"
+	"github.com/marshall/zero-ops-bot/internal/reminders"
+	"github.com/marshall/zero-ops-bot/internal/state"
+)
+
+var RemindCommand = &discordgo.ApplicationCommand{
+	Name:        "remind",
+	Description: "Set a one-time reminder",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "when",
+			Description: "e.g. 2h, tomorrow 9am, friday 17:00, 2026-11-01 08:00",
+			Type:        discordgo.ApplicationCommandOptionString,
+			Required:    true,
+		},
+		{
+			Name:        "text",
+			Description: "What to remind you about",
+			Type:        discordgo.ApplicationCommandOptionString,
+			Required:    true,
+		},
+		{
+			Name:        "dm",
+			Description: "Send the reminder as a DM instead of mentioning you here",
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Required:    false,
+		},
+	},
+}
+
+func NewRemindHandler(manager *reminders.Manager) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		var when, text string
+		dm := false
+		for _, opt := range i.ApplicationCommandData().Options {
+			switch opt.Name {
+			case "when":
+				when = opt.StringValue()
+			case "text":
+				text = opt.StringValue()
+			case "dm":
+				dm = opt.BoolValue()
+			}
+		}
+
+		msg, _ := RemindMessage(manager, InteractionUser(i).ID, i.ChannelID, when, text, dm)
+		RespondEphemeral(s, i, msg)
+	}
+}
+
+// RemindMessage schedules a reminder and returns the reply text, which
+// explains the error when it fails. It is shared by /remind and the mention
+// router.
+func RemindMessage(manager *reminders.Manager, userID, channelID, when, text string, dm bool) (string, error) {
+	at, err := dates.ParseWhen(when, manager.Now(state.UserLocation(userID)))
+	if err != nil {
+		return "Failed to set reminder: " + err.Error(), err
+	}
+
+	r, err := manager.Add(reminders.Reminder{
+		UserID:    userID,
+		ChannelID: channelID,
+		DM:        dm,
+		Text:      text,
+		At:        at,
+	})
+	if err != nil {
+		return "Failed to set reminder: " + err.Error(), err
+	}
+	return reminderSetMessage(r), nil
+}
+
+func reminderSetMessage(r reminders.Reminder) string {
+	return fmt.Sprintf("⏰ I'll remind you <t:%d:F> (<t:%d:R>): %s", r.At.Unix(), r.At.Unix(), r.Text)
+}
+
+// NewReminderButtonHandler handles the snooze and done buttons on a fired
+// reminder: "remind:<id>:<15m|1h|1d|done>".
+func NewReminderButtonHandler(manager *reminders.Manager) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		parts := strings.Split(i.MessageComponentData().CustomID, ":")
+		if len(parts) != 3 {
+			return
+		}
+		id, arg := parts[1], parts[2]
+		userID := InteractionUser(i).ID
+
+		if arg == "done" {
+			if err := manager.Cancel(id, userID); err != nil {
+				RespondEphemeral(s, i, "Couldn't update the reminder: "+err.Error())
+				return
+			}
+			UpdateMessage(s, i, i.Message.Content+"\n✅ Done")
+			return
+		}
+
+		for _, opt := range reminders.SnoozeOptions {
+			if opt.Arg != arg {
+				continue
+			}
+			r, err := manager.Snooze(id, userID, opt.After)
+			if err != nil {
+				RespondEphemeral(s, i, "Couldn't snooze the reminder: "+err.Error())
+				return
+			}
+			UpdateMessage(s, i, fmt.Sprintf("%s\n💤 Snoozed until <t:%d:F>", i.Message.Content, r.At.Unix()))
+			return
+		}
+	}
+}
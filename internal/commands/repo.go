@@ -61,7 +61,7 @@ var RepoCommand = &discordgo.ApplicationCommand{
 func HandleRepoCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	options := i.ApplicationCommandData().Options
 	if len(options) == 0 {
-		respond(s, i, "No subcommand provided")
+		Respond(s, i, "No subcommand provided")
 		return
 	}
 
@@ -95,18 +95,18 @@ func handleRepoAdd(s *discordgo.Session, i *discordgo.InteractionCreate, opts []
 	})
 
 	if err != nil {
-		respond(s, i, "Failed to add repo: "+err.Error())
+		Respond(s, i, "Failed to add repo: "+err.Error())
 		return
 	}
 
-	respond(s, i, fmt.Sprintf("Added repo **%s**", name))
+	Respond(s, i, fmt.Sprintf("Added repo **%s**", name))
 }
 
 func handleRepoList(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	repos := metadata.ListRepos()
 
 	if len(repos) == 0 {
-		respond(s, i, "No repositories configured")
+		Respond(s, i, "No repositories configured")
 		return
 	}
 
@@ -116,24 +116,15 @@ func handleRepoList(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		sb.WriteString(fmt.Sprintf("- **%s** (`%s`): %s\n", repo.Name, repo.Path, repo.Description))
 	}
 
-	respond(s, i, sb.String())
+	Respond(s, i, sb.String())
 }
 
 func handleRepoRemove(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption) {
 	name := opts[0].StringValue()
 
 	if metadata.RemoveRepo(name) {
-		respond(s, i, fmt.Sprintf("Removed repo **%s**", name))
+		Respond(s, i, fmt.Sprintf("Removed repo **%s**", name))
 	} else {
-		respond(s, i, fmt.Sprintf("Repo **%s** not found", name))
+		Respond(s, i, fmt.Sprintf("Repo **%s** not found", name))
 	}
 }
-
-func respond(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
-	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: content,
-		},
-	})
-}
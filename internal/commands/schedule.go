@@ -72,7 +72,7 @@ func NewScheduleHandler(reloader ScheduleReloader) func(s *discordgo.Session, i
 	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		options := i.ApplicationCommandData().Options
 		if len(options) == 0 {
-			respond(s, i, "No subcommand provided")
+			Respond(s, i, "No subcommand provided")
 			return
 		}
 
@@ -91,7 +91,7 @@ func handleScheduleList(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	schedules := metadata.ListSchedules()
 
 	if len(schedules) == 0 {
-		respond(s, i, "No schedules configured")
+		Respond(s, i, "No schedules configured")
 		return
 	}
 
@@ -105,10 +105,13 @@ func handleScheduleList(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		if sched.IncludeRepos {
 			flags += " [repos]"
 		}
+		if sched.IncludeTodos {
+			flags += " [todos]"
+		}
 		sb.WriteString(fmt.Sprintf("- **%s** `%s` → `%s`%s\n", sched.Name, sched.Cron, sched.Command, flags))
 	}
 
-	respond(s, i, sb.String())
+	Respond(s, i, sb.String())
 }
 
 func handleScheduleAdd(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, reloader ScheduleReloader) {
@@ -138,18 +141,18 @@ func handleScheduleAdd(s *discordgo.Session, i *discordgo.InteractionCreate, opt
 	}
 
 	if err := metadata.AddSchedule(schedule); err != nil {
-		respond(s, i, "Failed to add schedule: "+err.Error())
+		Respond(s, i, "Failed to add schedule: "+err.Error())
 		return
 	}
 
 	if reloader != nil {
 		if err := reloader.Reload(); err != nil {
-			respond(s, i, fmt.Sprintf("Schedule saved but reload failed: %v", err))
+			Respond(s, i, fmt.Sprintf("Schedule saved but reload failed: %v", err))
 			return
 		}
 	}
 
-	respond(s, i, fmt.Sprintf("Added schedule **%s** (`%s` → `%s`)", name, cronExpr, command))
+	Respond(s, i, fmt.Sprintf("Added schedule **%s** (`%s` → `%s`)", name, cronExpr, command))
 }
 
 func handleScheduleRemove(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, reloader ScheduleReloader) {
@@ -159,8 +162,8 @@ func handleScheduleRemove(s *discordgo.Session, i *discordgo.InteractionCreate,
 		if reloader != nil {
 			reloader.Reload()
 		}
-		respond(s, i, fmt.Sprintf("Removed schedule **%s**", name))
+		Respond(s, i, fmt.Sprintf("Removed schedule **%s**", name))
 	} else {
-		respond(s, i, fmt.Sprintf("Schedule **%s** not found", name))
+		Respond(s, i, fmt.Sprintf("Schedule **%s** not found", name))
 	}
 }
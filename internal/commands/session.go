@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/metadata"
+	"github.com/marshall/zero-ops-bot/internal/state"
+)
+
+var SessionCommand = &discordgo.ApplicationCommand{
+	Name:        "session",
+	Description: "Manage the bot's memory for this thread",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "reset",
+			Description: "Start a fresh session so earlier messages are forgotten",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+		},
+	},
+}
+
+var ContextCommand = &discordgo.ApplicationCommand{
+	Name:        "context",
+	Description: "Focus this thread on one repository",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "repo",
+			Description: "Repository name (omit to send all repositories again)",
+			Type:        discordgo.ApplicationCommandOptionString,
+			Required:    false,
+		},
+	},
+}
+
+func HandleSessionCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 || options[0].Name != "reset" {
+		Respond(s, i, "No subcommand provided")
+		return
+	}
+
+	// Reset the channel's session and the caller's /ask session in it.
+	for _, key := range []string{i.ChannelID, state.AskSessionKey(i.ChannelID, InteractionUser(i).ID)} {
+		if err := state.ResetSession(key); err != nil {
+			Respond(s, i, fmt.Sprintf("Failed to reset session: %v", err))
+			return
+		}
+	}
+	Respond(s, i, "Session reset. I'll start this conversation from scratch.")
+}
+
+func HandleContextCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var name string
+	for _, opt := range i.ApplicationCommandData().Options {
+		if opt.Name == "repo" {
+			name = opt.StringValue()
+		}
+	}
+
+	if name != "" {
+		if _, ok := metadata.Get().FindRepo(name); !ok {
+			Respond(s, i, fmt.Sprintf("Repository **%s** not found. Use `/repo list` to see registered repositories.", name))
+			return
+		}
+	}
+
+	if err := state.BindRepo(i.ChannelID, name); err != nil {
+		Respond(s, i, fmt.Sprintf("Failed to update context: %v", err))
+		return
+	}
+
+	if name == "" {
+		Respond(s, i, "Context cleared. All repositories will be sent again.")
+		return
+	}
+	Respond(s, i, fmt.Sprintf("This thread is now focused on **%s**.", name))
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/state"
+)
+
+var SettingsCommand = &discordgo.ApplicationCommand{
+	Name:        "settings",
+	Description: "Manage your personal settings",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "timezone",
+			Description: "Set the timezone that decides which day \"today\" is for your notes",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "zone",
+					Description: "IANA name such as Europe/Berlin (omit to use the bot's timezone)",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
+			},
+		},
+	},
+}
+
+// NewSettingsHandler handles /settings; defaultLoc is the bot's timezone,
+// used by everyone who hasn't set their own.
+func NewSettingsHandler(defaultLoc *time.Location) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		options := i.ApplicationCommandData().Options
+		if len(options) == 0 || options[0].Name != "timezone" {
+			Respond(s, i, "No subcommand provided")
+			return
+		}
+
+		var zone string
+		for _, opt := range options[0].Options {
+			if opt.Name == "zone" {
+				zone = opt.StringValue()
+			}
+		}
+
+		user := InteractionUser(i)
+		if err := state.SetTimezone(user.ID, zone); err != nil {
+			RespondEphemeral(s, i, "Failed to set timezone: "+err.Error())
+			return
+		}
+
+		loc := state.UserLocation(user.ID)
+		if loc == nil {
+			loc = defaultLoc
+		}
+		now := time.Now().In(loc).Format("2006-01-02 15:04")
+		if zone == "" {
+			RespondEphemeral(s, i, fmt.Sprintf("Using the bot's timezone **%s** (now %s).", loc, now))
+			return
+		}
+		RespondEphemeral(s, i, fmt.Sprintf("Your timezone is now **%s** (now %s).", loc, now))
+	}
+}
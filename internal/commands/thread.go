@@ -0,0 +1,29 @@
+package commands
+
+import "github.com/bwmarrin/discordgo"
+
+var ThreadCommand = &discordgo.ApplicationCommand{
+	Name:        "thread",
+	Description: "Manage the current bot thread",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "close",
+			Description: "Close this thread, optionally saving a summary to notes",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "save",
+					Description: "Summarize the thread into notes (default: false)",
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Required:    false,
+				},
+				{
+					Name:        "category",
+					Description: "Note category for the summary (default: incidents)",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,184 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/notes"
+)
+
+var TodoCommand = &discordgo.ApplicationCommand{
+	Name:        "todo",
+	Description: "Track tasks in the notes",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "add",
+			Description: "Add a todo",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "text",
+					Description: "What needs doing",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+				{
+					Name:        "due",
+					Description: "Due date in YYYY-MM-DD format",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
+				{
+					Name:        "priority",
+					Description: "Priority",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "high", Value: "high"},
+						{Name: "medium", Value: "medium"},
+						{Name: "low", Value: "low"},
+					},
+				},
+				{
+					Name:        "category",
+					Description: "Category name (default: daily)",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:        "done",
+			Description: "Mark a todo as done",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "id",
+					Description: "Todo ID",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "list",
+			Description: "List open todos",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "all",
+					Description: "Include completed todos",
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:        "overdue",
+			Description: "List open todos past their due date",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+		},
+	},
+}
+
+func NewTodoHandler(store *notes.Store) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		options := i.ApplicationCommandData().Options
+		if len(options) == 0 {
+			Respond(s, i, "No subcommand provided")
+			return
+		}
+
+		switch options[0].Name {
+		case "add":
+			handleTodoAdd(s, i, options[0].Options, store)
+		case "done":
+			Respond(s, i, TodoDoneMessage(store, options[0].Options[0].StringValue(), InteractionUser(i).Username))
+		case "list":
+			all := false
+			for _, opt := range options[0].Options {
+				if opt.Name == "all" {
+					all = opt.BoolValue()
+				}
+			}
+			Respond(s, i, TodoListMessage(store, all))
+		case "overdue":
+			Respond(s, i, TodoOverdueMessage(store, userLocation(i)))
+		}
+	}
+}
+
+func handleTodoAdd(s *discordgo.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption, store *notes.Store) {
+	todo := notes.Note{Todo: true, Author: InteractionUser(i).Username}
+	for _, opt := range opts {
+		switch opt.Name {
+		case "text":
+			todo.Text = opt.StringValue()
+		case "due":
+			todo.Due = opt.StringValue()
+		case "priority":
+			todo.Priority = opt.StringValue()
+		case "category":
+			todo.Category = opt.StringValue()
+		}
+	}
+
+	saved, err := store.Add(todo, userLocation(i))
+	if err != nil {
+		Respond(s, i, "Failed to add todo: "+err.Error())
+		return
+	}
+	Respond(s, i, "Added todo "+saved.String())
+}
+
+func TodoDoneMessage(store *notes.Store, id, by string) string {
+	todo, err := store.Complete(id, by)
+	if err != nil {
+		return "Failed to complete todo: " + err.Error()
+	}
+	return fmt.Sprintf("Done: %s", todo.Text)
+}
+
+func TodoListMessage(store *notes.Store, all bool) string {
+	list, err := store.OpenTodos()
+	if all {
+		list, err = store.Todos()
+	}
+	if err != nil {
+		return "Failed to read todos: " + err.Error()
+	}
+	if len(list) == 0 {
+		return "No open todos"
+	}
+	return formatTodos("Todos", list)
+}
+
+func TodoOverdueMessage(store *notes.Store, loc *time.Location) string {
+	open, err := store.OpenTodos()
+	if err != nil {
+		return "Failed to read todos: " + err.Error()
+	}
+
+	today := store.Today(loc)
+	var overdue []notes.Note
+	for _, n := range open {
+		if n.Overdue(today) {
+			overdue = append(overdue, n)
+		}
+	}
+	if len(overdue) == 0 {
+		return "Nothing overdue"
+	}
+	return formatTodos("Overdue", overdue)
+}
+
+func formatTodos(title string, list []notes.Note) string {
+	var sb strings.Builder
+	sb.WriteString("**" + title + ":**\n")
+	for _, n := range list {
+		sb.WriteString(fmt.Sprintf("%s · %s\n", n, n.Source()))
+	}
+	return sb.String()
+}
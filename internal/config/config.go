@@ -2,20 +2,32 @@ package config
 
 import (
 	"errors"
+	"log"
 	"os"
 	"strings"
+	"time"
 )
 
 type Config struct {
-	DiscordToken     string
-	DiscordAppID     string
-	DiscordGuildID   string
-	N8nWebhookURL    string
-	N8nWebhookSecret string
-	AllowedChannels  []string
-	MetadataPath     string
-	Timezone         string
-	NotesDir         string
+	DiscordToken       string
+	DiscordAppID       string
+	DiscordGuildID     string
+	N8nWebhookURL      string
+	N8nWebhookSecret   string
+	AllowedChannels    []string
+	DMAllowedUsers     []string
+	DeleteReplies      bool
+	MetadataPath       string
+	Timezone           string
+	Location           *time.Location
+	NotesDir           string
+	DataDir            string
+	ForwardFeedback    bool
+	SummarizeOnArchive bool
+	NotesGit           bool
+	EmbeddingsURL      string
+	EmbeddingsModel    string
+	EmbeddingsAPIKey   string
 }
 
 func Load() (*Config, error) {
@@ -34,14 +46,7 @@ func Load() (*Config, error) {
 		return nil, errors.New("N8N_WEBHOOK_URL is required")
 	}
 
-	var allowedChannels []string
-	if channels := os.Getenv("ALLOWED_CHANNELS"); channels != "" {
-		for _, ch := range strings.Split(channels, ",") {
-			if trimmed := strings.TrimSpace(ch); trimmed != "" {
-				allowedChannels = append(allowedChannels, trimmed)
-			}
-		}
-	}
+	allowedChannels := splitList(os.Getenv("ALLOWED_CHANNELS"))
 
 	metadataPath := os.Getenv("METADATA_PATH")
 	if metadataPath == "" {
@@ -52,21 +57,57 @@ func Load() (*Config, error) {
 	if timezone == "" {
 		timezone = "Local"
 	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Printf("Invalid timezone %q, using Local: %v", timezone, err)
+		timezone, location = "Local", time.Local
+	}
 
 	notesDir := os.Getenv("NOTES_DIR")
 	if notesDir == "" {
 		notesDir = "./notes"
 	}
 
+	dataDir := os.Getenv("DATA_DIR")
+	if dataDir == "" {
+		dataDir = "./data"
+	}
+
+	embeddingsURL := os.Getenv("EMBEDDINGS_URL")
+	embeddingsModel := os.Getenv("EMBEDDINGS_MODEL")
+	if embeddingsURL != "" && embeddingsModel == "" {
+		return nil, errors.New("EMBEDDINGS_MODEL is required when EMBEDDINGS_URL is set")
+	}
+
 	return &Config{
-		DiscordToken:     token,
-		DiscordAppID:     appID,
-		DiscordGuildID:   os.Getenv("DISCORD_GUILD_ID"),
-		N8nWebhookURL:    webhookURL,
-		N8nWebhookSecret: os.Getenv("N8N_WEBHOOK_SECRET"),
-		AllowedChannels:  allowedChannels,
-		MetadataPath:     metadataPath,
-		Timezone:         timezone,
-		NotesDir:         notesDir,
+		DiscordToken:       token,
+		DiscordAppID:       appID,
+		DiscordGuildID:     os.Getenv("DISCORD_GUILD_ID"),
+		N8nWebhookURL:      webhookURL,
+		N8nWebhookSecret:   os.Getenv("N8N_WEBHOOK_SECRET"),
+		AllowedChannels:    allowedChannels,
+		DMAllowedUsers:     splitList(os.Getenv("DM_ALLOWED_USERS")),
+		DeleteReplies:      os.Getenv("DELETE_REPLIES_WITH_SOURCE") == "true",
+		MetadataPath:       metadataPath,
+		Timezone:           timezone,
+		Location:           location,
+		NotesDir:           notesDir,
+		NotesGit:           os.Getenv("NOTES_GIT") == "true",
+		DataDir:            dataDir,
+		ForwardFeedback:    os.Getenv("FEEDBACK_TO_N8N") == "true",
+		SummarizeOnArchive: os.Getenv("SUMMARIZE_ON_ARCHIVE") == "true",
+		EmbeddingsURL:      embeddingsURL,
+		EmbeddingsModel:    embeddingsModel,
+		EmbeddingsAPIKey:   os.Getenv("EMBEDDINGS_API_KEY"),
 	}, nil
 }
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
@@ -0,0 +1,188 @@
+package dates
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout is the date format of daily note files.
+const Layout = "2006-01-02"
+
+// MaxDays caps how long a range can be.
+const MaxDays = 366
+
+var (
+	// "-3d", "3d ago", "2 weeks ago"
+	relativeDay = regexp.MustCompile(`^(?:-(\d+)\s*(d|days?|w|weeks?)|(\d+)\s*(d|days?|w|weeks?) ago)$`)
+	// "last 7 days", "past 2 weeks"
+	lastSpan = regexp.MustCompile(`^(?:last|past) (\d+) ?(d|days?|w|weeks?)$`)
+)
+
+// Range is a span of whole days, From and To inclusive, at midnight in their
+// location.
+type Range struct {
+	From time.Time
+	To   time.Time
+}
+
+// Days lists the range's dates, oldest first.
+func (r Range) Days() []string {
+	var days []string
+	for d := r.From; !d.After(r.To); d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format(Layout))
+	}
+	return days
+}
+
+// Single reports whether the range is one day.
+func (r Range) Single() bool {
+	return r.From.Equal(r.To)
+}
+
+func (r Range) String() string {
+	if r.Single() {
+		return r.From.Format(Layout)
+	}
+	return r.From.Format(Layout) + " – " + r.To.Format(Layout)
+}
+
+// ParseDay reads one day: "today", "yesterday", "tomorrow", a weekday
+// ("friday" is the most recent one, today included; "last friday" the one
+// before today), "-3d", "2 weeks ago" or YYYY-MM-DD.
+func ParseDay(input string, now time.Time) (time.Time, error) {
+	s := strings.Join(strings.Fields(strings.ToLower(input)), " ")
+	today := midnight(now)
+
+	switch s {
+	case "":
+		return time.Time{}, fmt.Errorf("date is required")
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if t, err := time.ParseInLocation(Layout, s, now.Location()); err == nil {
+		return t, nil
+	}
+
+	if m := relativeDay.FindStringSubmatch(s); m != nil {
+		n, unit := m[1], m[2]
+		if n == "" {
+			n, unit = m[3], m[4]
+		}
+		return today.AddDate(0, 0, -days(n, unit)), nil
+	}
+
+	day, last := strings.CutPrefix(s, "last ")
+	if wd, ok := weekdays[day]; ok {
+		back := (int(today.Weekday()) - int(wd) + 7) % 7
+		if last && back == 0 {
+			back = 7
+		}
+		return today.AddDate(0, 0, -back), nil
+	}
+
+	return time.Time{}, fmt.Errorf("can't understand the date %q, try yesterday, last friday, -3d or YYYY-MM-DD", input)
+}
+
+// ParseRange reads "this week", "last week" (weeks start on Monday), "this
+// month", "last month", "last 7 days", "A..B" or "A to B" with days in
+// ParseDay's syntax, or a single day.
+func ParseRange(input string, now time.Time) (Range, error) {
+	s := strings.Join(strings.Fields(strings.ToLower(input)), " ")
+	today := midnight(now)
+
+	switch s {
+	case "this week":
+		start := startOfWeek(today)
+		return Range{From: start, To: start.AddDate(0, 0, 6)}, nil
+	case "last week":
+		start := startOfWeek(today).AddDate(0, 0, -7)
+		return Range{From: start, To: start.AddDate(0, 0, 6)}, nil
+	case "this month":
+		start := today.AddDate(0, 0, 1-today.Day())
+		return Range{From: start, To: start.AddDate(0, 1, -1)}, nil
+	case "last month":
+		start := today.AddDate(0, 0, 1-today.Day()).AddDate(0, -1, 0)
+		return Range{From: start, To: start.AddDate(0, 1, -1)}, nil
+	}
+
+	if m := lastSpan.FindStringSubmatch(s); m != nil {
+		n := days(m[1], m[2])
+		if n < 1 {
+			return Range{}, fmt.Errorf("can't understand %q", input)
+		}
+		return checked(Range{From: today.AddDate(0, 0, 1-n), To: today})
+	}
+
+	for _, sep := range []string{"..", " to "} {
+		if from, to, ok := strings.Cut(s, sep); ok {
+			return Between(from, to, now)
+		}
+	}
+
+	day, err := ParseDay(s, now)
+	if err != nil {
+		return Range{}, err
+	}
+	return Range{From: day, To: day}, nil
+}
+
+// Between builds a range from separate from and to inputs, each a day or a
+// range (from takes its start, to its end). Without to the range runs
+// through today.
+func Between(from, to string, now time.Time) (Range, error) {
+	if strings.TrimSpace(from) == "" {
+		return Range{}, fmt.Errorf("from date is required")
+	}
+
+	start, err := ParseRange(from, now)
+	if err != nil {
+		return Range{}, err
+	}
+	r := Range{From: start.From, To: midnight(now)}
+
+	if strings.TrimSpace(to) != "" {
+		end, err := ParseRange(to, now)
+		if err != nil {
+			return Range{}, err
+		}
+		r.To = end.To
+	}
+	return checked(r)
+}
+
+func checked(r Range) (Range, error) {
+	if r.From.After(r.To) {
+		return Range{}, fmt.Errorf("%s is after %s", r.From.Format(Layout), r.To.Format(Layout))
+	}
+	// Round so a daylight saving change inside the range doesn't matter.
+	if span := int(math.Round(r.To.Sub(r.From).Hours()/24)) + 1; span > MaxDays {
+		return Range{}, fmt.Errorf("ranges are limited to %d days", MaxDays)
+	}
+	return r, nil
+}
+
+func days(n, unit string) int {
+	count, _ := strconv.Atoi(n)
+	if unit[0] == 'w' {
+		return 7 * count
+	}
+	return count
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func startOfWeek(day time.Time) time.Time {
+	back := (int(day.Weekday()) + 6) % 7 // Monday is 0
+	return day.AddDate(0, 0, -back)
+}
@@ -0,0 +1,116 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+// Wednesday
+var testNow = time.Date(2026, 10, 21, 14, 30, 0, 0, time.FixedZone("KST", 9*60*60))
+
+func day(s string) time.Time {
+	t, err := time.ParseInLocation(Layout, s, testNow.Location())
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseDay(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"today", "2026-10-21"},
+		{"Yesterday", "2026-10-20"},
+		{"tomorrow", "2026-10-22"},
+		{"2026-01-05", "2026-01-05"},
+		{"-3d", "2026-10-18"},
+		{"-1w", "2026-10-14"},
+		{"3 days ago", "2026-10-18"},
+		{"2 weeks ago", "2026-10-07"},
+		{"friday", "2026-10-16"},
+		{"wednesday", "2026-10-21"},
+		{"last friday", "2026-10-16"},
+		{"last wednesday", "2026-10-14"},
+		{"last  Monday", "2026-10-19"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDay(tt.input, testNow)
+		if err != nil {
+			t.Errorf("ParseDay(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(day(tt.want)) {
+			t.Errorf("ParseDay(%q) = %s, want %s", tt.input, got.Format(Layout), tt.want)
+		}
+	}
+}
+
+func TestParseDay_Invalid(t *testing.T) {
+	for _, input := range []string{"", "soon", "2026-13-01", "next friday", "-3x", "3d"} {
+		if got, err := ParseDay(input, testNow); err == nil {
+			t.Errorf("ParseDay(%q) = %v, expected an error", input, got)
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		from, to string
+	}{
+		{"this week", "2026-10-19", "2026-10-25"},
+		{"last week", "2026-10-12", "2026-10-18"},
+		{"this month", "2026-10-01", "2026-10-31"},
+		{"last month", "2026-09-01", "2026-09-30"},
+		{"last 7 days", "2026-10-15", "2026-10-21"},
+		{"past 2 weeks", "2026-10-08", "2026-10-21"},
+		{"2026-10-01..2026-10-03", "2026-10-01", "2026-10-03"},
+		{"last friday to yesterday", "2026-10-16", "2026-10-20"},
+		{"last week..this week", "2026-10-12", "2026-10-25"},
+		{"yesterday", "2026-10-20", "2026-10-20"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRange(tt.input, testNow)
+		if err != nil {
+			t.Errorf("ParseRange(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if !got.From.Equal(day(tt.from)) || !got.To.Equal(day(tt.to)) {
+			t.Errorf("ParseRange(%q) = %s, want %s – %s", tt.input, got, tt.from, tt.to)
+		}
+	}
+}
+
+func TestParseRange_Invalid(t *testing.T) {
+	for _, input := range []string{"", "next week", "today..yesterday", "2024-01-01..2026-01-01", "last 0 days"} {
+		if got, err := ParseRange(input, testNow); err == nil {
+			t.Errorf("ParseRange(%q) = %s, expected an error", input, got)
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	r, err := Between("-2d", "", testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Days(); len(got) != 3 || got[0] != "2026-10-19" || got[2] != "2026-10-21" {
+		t.Errorf("Expected 2026-10-19 through 2026-10-21, got %v", got)
+	}
+
+	r, err = Between("last week", "last week", testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.String() != "2026-10-12 – 2026-10-18" {
+		t.Errorf("Expected last week, got %s", r)
+	}
+
+	if _, err := Between("", "today", testNow); err == nil {
+		t.Error("Expected an error without a from date")
+	}
+}
@@ -0,0 +1,147 @@
+// Package dates reads the dates and times people type: reminder times such
+// as "tomorrow 9am", single days such as "last friday" and ranges such as
+// "this week".
+package dates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultHour is used when a reminder names a day but no time.
+const defaultHour = 9
+
+var (
+	durationPart = regexp.MustCompile(`(\d+)\s*(minutes?|mins?|m|hours?|hrs?|h|days?|d|weeks?|w)`)
+	durationFull = regexp.MustCompile(`^(?:\d+\s*(?:minutes?|mins?|m|hours?|hrs?|h|days?|d|weeks?|w)\s*)+$`)
+	clockTime    = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// ParseWhen turns "2h", "1h30m", "in 3 days", "tomorrow 9am", "friday 17:00",
+// "18:30" or "2026-11-01 08:00" into a time after now, in now's location.
+func ParseWhen(input string, now time.Time) (time.Time, error) {
+	s := strings.Join(strings.Fields(strings.ToLower(input)), " ")
+	s = strings.TrimPrefix(s, "in ")
+	s = strings.TrimPrefix(s, "at ")
+	if s == "" {
+		return time.Time{}, fmt.Errorf("when is required")
+	}
+
+	at, err := parseWhen(s, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !at.After(now) {
+		return time.Time{}, fmt.Errorf("%q is in the past", input)
+	}
+	return at, nil
+}
+
+func parseWhen(s string, now time.Time) (time.Time, error) {
+	if durationFull.MatchString(s) {
+		at := now
+		for _, m := range durationPart.FindAllStringSubmatch(s, -1) {
+			n, _ := strconv.Atoi(m[1])
+			switch m[2][0] {
+			case 'm':
+				at = at.Add(time.Duration(n) * time.Minute)
+			case 'h':
+				at = at.Add(time.Duration(n) * time.Hour)
+			case 'd':
+				at = at.AddDate(0, 0, n)
+			case 'w':
+				at = at.AddDate(0, 0, 7*n)
+			}
+		}
+		return at, nil
+	}
+
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			if layout == "2006-01-02" {
+				t = t.Add(defaultHour * time.Hour)
+			}
+			return t, nil
+		}
+	}
+
+	day, clock, _ := strings.Cut(s, " ")
+	var date time.Time
+	switch {
+	case day == "today":
+		date = now
+	case day == "tomorrow":
+		date = now.AddDate(0, 0, 1)
+	default:
+		if wd, ok := weekdays[day]; ok {
+			ahead := (int(wd) - int(now.Weekday()) + 7) % 7
+			if ahead == 0 {
+				ahead = 7
+			}
+			date = now.AddDate(0, 0, ahead)
+		}
+	}
+
+	if date.IsZero() {
+		// A bare time is the next time the clock shows it.
+		hour, minute, ok := parseClock(s)
+		if !ok {
+			return time.Time{}, fmt.Errorf("can't understand %q, try 2h, tomorrow 9am or 2026-11-01 08:00", s)
+		}
+		at := atClock(now, hour, minute)
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, nil
+	}
+
+	if clock == "" {
+		return atClock(date, defaultHour, 0), nil
+	}
+	hour, minute, ok := parseClock(clock)
+	if !ok {
+		return time.Time{}, fmt.Errorf("can't understand the time %q", clock)
+	}
+	return atClock(date, hour, minute), nil
+}
+
+// parseClock reads "9am", "9:30 pm" or "21:30". A bare number needs am/pm.
+func parseClock(s string) (int, int, bool) {
+	m := clockTime.FindStringSubmatch(s)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return 0, 0, false
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+
+	if m[3] != "" {
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	}
+
+	if hour > 23 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
+
+func atClock(day time.Time, hour, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
@@ -0,0 +1,55 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWhen(t *testing.T) {
+	loc := time.FixedZone("KST", 9*60*60)
+	// Wednesday
+	now := time.Date(2026, 10, 21, 14, 30, 0, 0, loc)
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"2h", now.Add(2 * time.Hour)},
+		{"1h30m", now.Add(90 * time.Minute)},
+		{"in 45 minutes", now.Add(45 * time.Minute)},
+		{"3 days", now.AddDate(0, 0, 3)},
+		{"1w", now.AddDate(0, 0, 7)},
+		{"tomorrow", time.Date(2026, 10, 22, 9, 0, 0, 0, loc)},
+		{"tomorrow 9am", time.Date(2026, 10, 22, 9, 0, 0, 0, loc)},
+		{"Tomorrow 9:15 PM", time.Date(2026, 10, 22, 21, 15, 0, 0, loc)},
+		{"today 17:00", time.Date(2026, 10, 21, 17, 0, 0, 0, loc)},
+		{"friday 8am", time.Date(2026, 10, 23, 8, 0, 0, 0, loc)},
+		{"wednesday 10:00", time.Date(2026, 10, 28, 10, 0, 0, 0, loc)},
+		{"18:30", time.Date(2026, 10, 21, 18, 30, 0, 0, loc)},
+		{"at 12am", time.Date(2026, 10, 22, 0, 0, 0, 0, loc)},
+		{"9am", time.Date(2026, 10, 22, 9, 0, 0, 0, loc)},
+		{"2026-11-01 08:00", time.Date(2026, 11, 1, 8, 0, 0, 0, loc)},
+		{"2026-11-01", time.Date(2026, 11, 1, 9, 0, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseWhen(tt.input, now)
+		if err != nil {
+			t.Errorf("ParseWhen(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseWhen(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseWhen_Invalid(t *testing.T) {
+	now := time.Date(2026, 10, 21, 14, 30, 0, 0, time.UTC)
+
+	for _, input := range []string{"", "soon", "today 9am", "2026-01-01 08:00", "tomorrow 25:00", "13pm", "7"} {
+		if got, err := ParseWhen(input, now); err == nil {
+			t.Errorf("ParseWhen(%q) = %v, expected an error", input, got)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package feedback
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Ratings a user can give a bot answer.
+const (
+	RatingUp            = "up"
+	RatingDown          = "down"
+	RatingWrongWorkflow = "wrong_workflow"
+)
+
+// Entry is one piece of feedback on a bot answer, kept as a line of JSONL so
+// the dataset can be fed straight into evaluation tooling.
+type Entry struct {
+	Timestamp string `json:"timestamp"`
+	Rating    string `json:"rating"`
+	UserID    string `json:"user_id"`
+	SessionID string `json:"session_id,omitempty"`
+	ThreadID  string `json:"thread_id,omitempty"`
+	MessageID string `json:"message_id"`
+	Command   string `json:"command"`
+	Prompt    string `json:"prompt"`
+	Routed    string `json:"routed,omitempty"`
+	Response  string `json:"response"`
+}
+
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewStore(dataDir string) (*Store, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("create directory %s: %w", dataDir, err)
+	}
+	return &Store{path: filepath.Join(dataDir, "feedback.jsonl")}, nil
+}
+
+func (s *Store) Add(entry Entry) error {
+	if entry.Timestamp == "" {
+		entry.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Export returns the whole dataset as JSONL.
+func (s *Store) Export() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
@@ -0,0 +1,82 @@
+package guard
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pattern is a known prompt-injection shape with a reason for the logs.
+type pattern struct {
+	reason string
+	re     *regexp.Regexp
+}
+
+// Patterns are matched against normalized text: lowercased, zero-width
+// characters removed and whitespace collapsed to single spaces.
+var patterns = []pattern{
+	{"instruction override", regexp.MustCompile(`\b(ignore|disregard|forget|override|bypass)\b( \w+){0,3} (previous|prior|above|earlier|preceding|all|your|system|the) (instructions?|prompts?|rules|directives|guidelines|context)\b`)},
+	{"instruction override", regexp.MustCompile(`\b(new|updated|real) (system )?instructions ?:`)},
+	{"role hijack", regexp.MustCompile(`\byou are (now|no longer)\b.*\b(router|assistant|bot|ai|model|dan|unrestricted|jailbroken)\b`)},
+	{"role hijack", regexp.MustCompile(`\b(developer|jailbreak|dan|god) mode\b`)},
+	{"prompt extraction", regexp.MustCompile(`\b(reveal|print|show|repeat|output|leak)\b( \w+){0,2} (system|initial|hidden|original|router) (prompt|instructions|context)\b`)},
+	{"delimiter forgery", regexp.MustCompile(`={3,} ?(end )?(user message|system context|available workflows)`)},
+	{"delimiter forgery", regexp.MustCompile(`<\|?(im_start|im_end|system|endoftext)\|?>|\[/?inst\]|</?(system|user_message)>`)},
+	{"delimiter forgery", regexp.MustCompile(`(^|\n) ?(\[|\{|<<)(system|assistant|sys)(\]|\}|>>) ?:|<</?sys>>`)},
+	{"routing forgery", regexp.MustCompile(`\b(classify|route) (this|it|me|the message) as "?(infra|health)\b`)},
+}
+
+var (
+	zeroWidth  = strings.NewReplacer("\u200b", "", "\u200c", "", "\u200d", "", "\u2060", "", "\ufeff", "")
+	whitespace = regexp.MustCompile(`[ \t\r\f\v]+`)
+	delimiters = regexp.MustCompile(`={3,}|<\|[a-z_]+\|>`)
+)
+
+// Check reports whether content looks like a prompt-injection attempt and, if
+// so, which kind. workflows are the catalog names a forged routing decision
+// would pick. It is a cheap prefilter, not a replacement for the router's
+// own guard rules.
+func Check(content string, workflows []string) (string, bool) {
+	normalized := normalize(content)
+	for _, p := range patterns {
+		if p.re.MatchString(normalized) {
+			return p.reason, true
+		}
+	}
+	if re := decisionPattern(workflows); re != nil && re.MatchString(normalized) {
+		return "routing forgery", true
+	}
+	return "", false
+}
+
+// decisionPattern matches a forged router decision: a "command" naming one
+// of workflows next to a "content" key, in either order. Other JSON with a
+// "command" key, such as package.json scripts, passes.
+func decisionPattern(workflows []string) *regexp.Regexp {
+	if len(workflows) == 0 {
+		return nil
+	}
+	names := make([]string, len(workflows))
+	for i, name := range workflows {
+		names[i] = regexp.QuoteMeta(strings.ToLower(name))
+	}
+	command := `"command"\s*:\s*"(` + strings.Join(names, "|") + `)"`
+	content := `"content"\s*:`
+	return regexp.MustCompile(command + `\s*,\s*` + content + `|` + content + `\s*"(\\.|[^"\\])*"\s*,\s*` + command)
+}
+
+// Sanitize neutralizes sequences the router prompt uses as delimiters so
+// untrusted text can't close or open a prompt section.
+func Sanitize(content string) string {
+	content = zeroWidth.Replace(content)
+	return delimiters.ReplaceAllStringFunc(content, func(m string) string {
+		if strings.HasPrefix(m, "=") {
+			return "="
+		}
+		return strings.Trim(m, "<|>")
+	})
+}
+
+func normalize(content string) string {
+	content = zeroWidth.Replace(strings.ToLower(content))
+	return whitespace.ReplaceAllString(content, " ")
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/commands"
+	"github.com/marshall/zero-ops-bot/internal/state"
+)
+
+// HandleAskCommand runs /ask through the same pipeline as mentions, answering
+// in the interaction instead of a thread.
+func (p *Pipeline) HandleAskCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var prompt, workflow string
+	var private bool
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "prompt":
+			prompt = opt.StringValue()
+		case "workflow":
+			workflow = opt.StringValue()
+		case "private":
+			private = opt.BoolValue()
+		}
+	}
+
+	var flags discordgo.MessageFlags
+	if private {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: flags},
+	})
+	if err != nil {
+		return
+	}
+
+	user := commands.InteractionUser(i)
+	p.run(s, &conversation{
+		guildID:     i.GuildID,
+		channelID:   i.ChannelID,
+		threadID:    i.ChannelID,
+		sessionID:   state.ThreadIDToSessionID(state.AskSessionKey(i.ChannelID, user.ID)),
+		author:      user,
+		member:      i.Member,
+		content:     prompt,
+		forced:      workflow,
+		interaction: i.Interaction,
+		ephemeral:   private,
+	})
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/commands"
+	"github.com/marshall/zero-ops-bot/internal/metadata"
+	"github.com/marshall/zero-ops-bot/internal/services"
+	"github.com/marshall/zero-ops-bot/internal/state"
+)
+
+// defaultCandidates are offered when the router gives no candidates of its own.
+var defaultCandidates = []string{"infra", "chat", "note"}
+
+// clarification is a conversation parked until the user picks a workflow.
+type clarification struct {
+	conv   *conversation
+	routed *services.AnalyzeResponse
+}
+
+func needsClarification(meta metadata.Metadata, analyzed *services.AnalyzeResponse) bool {
+	return meta.ClarifyThreshold > 0 &&
+		analyzed.Confidence != nil &&
+		*analyzed.Confidence < meta.ClarifyThreshold
+}
+
+// clarify asks the user which workflow to run, offering the router's
+// candidates or a default set.
+func (p *Pipeline) clarify(s *discordgo.Session, c *conversation, meta metadata.Metadata, routed *services.AnalyzeResponse) {
+	candidates := defaultCandidates
+	if len(routed.Candidates) > 0 {
+		candidates = routed.Candidates
+		if !slices.Contains(candidates, routed.Command) {
+			candidates = append([]string{routed.Command}, candidates...)
+		}
+	}
+
+	id := state.PutPending(&clarification{conv: c, routed: routed})
+
+	var buttons []discordgo.MessageComponent
+	for _, name := range candidates {
+		if name == "reject" || len(buttons) == 5 {
+			continue
+		}
+		if _, ok := meta.FindWorkflow(name); !ok {
+			continue
+		}
+
+		label := "Run as " + name
+		if name == "note" {
+			label = "Save as note"
+		}
+		buttons = append(buttons, discordgo.Button{
+			Label:    label,
+			Style:    discordgo.SecondaryButton,
+			CustomID: "clarify:" + id + ":" + name,
+		})
+	}
+
+	if len(buttons) == 0 {
+		state.TakePending(id)
+		c.fail(s, "Sorry, I couldn't work out what to do with that. Could you rephrase?")
+		return
+	}
+
+	c.setStatus(s, "❓")
+
+	_, err := c.send(s, &discordgo.MessageSend{
+		Content: "I'm not sure how to handle this. What should I do?",
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: buttons},
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to send clarification: %v", err)
+		state.TakePending(id)
+		c.fail(s, "Sorry, I couldn't process that request. Please try again.")
+	}
+}
+
+// HandleClarification continues a parked conversation with the workflow the
+// user picked.
+func (p *Pipeline) HandleClarification(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	if len(parts) != 3 {
+		return
+	}
+
+	v, ok := state.PeekPending(parts[1])
+	if !ok {
+		commands.RespondEphemeral(s, i, "This request has expired.")
+		return
+	}
+	cl := v.(*clarification)
+
+	user := commands.InteractionUser(i)
+	if user.ID != cl.conv.author.ID {
+		commands.RespondEphemeral(s, i, "Only the person who asked can pick a workflow.")
+		return
+	}
+
+	if _, ok := state.TakePending(parts[1]); !ok {
+		commands.RespondEphemeral(s, i, "This request has already been handled.")
+		return
+	}
+
+	command := parts[2]
+	commands.UpdateMessage(s, i, fmt.Sprintf("Running as **%s**.", command))
+
+	ctx, cancel := context.WithTimeout(context.Background(), pipelineTimeout)
+	defer cancel()
+
+	p.dispatch(ctx, s, cl.conv, metadata.Get(), &services.AnalyzeResponse{
+		Command: command,
+		Content: clarifiedContent(cl, command),
+	})
+}
+
+// clarifiedContent reuses the router's content when the user confirmed its
+// own pick, and otherwise builds content from the original message.
+func clarifiedContent(cl *clarification, command string) string {
+	if cl.routed.Command == command && cl.routed.Content != "" {
+		return cl.routed.Content
+	}
+
+	return fallbackContent(command, cl.conv.content)
+}
+
+// fallbackContent builds workflow content straight from the user's message
+// when no router content is available.
+func fallbackContent(command, message string) string {
+	if command == "note" {
+		action, _ := json.Marshal(noteAction{Action: "add", Text: message, Category: "daily"})
+		return string(action)
+	}
+	return message
+}
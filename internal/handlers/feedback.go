@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/commands"
+	"github.com/marshall/zero-ops-bot/internal/feedback"
+	"github.com/marshall/zero-ops-bot/internal/services"
+	"github.com/marshall/zero-ops-bot/internal/state"
+)
+
+func feedbackButtons() []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{Emoji: &discordgo.ComponentEmoji{Name: "👍"}, Style: discordgo.SecondaryButton, CustomID: "feedback:" + feedback.RatingUp},
+				discordgo.Button{Emoji: &discordgo.ComponentEmoji{Name: "👎"}, Style: discordgo.SecondaryButton, CustomID: "feedback:" + feedback.RatingDown},
+				discordgo.Button{Label: "Wrong workflow", Style: discordgo.SecondaryButton, CustomID: "feedback:" + feedback.RatingWrongWorkflow},
+			},
+		},
+	}
+}
+
+// HandleFeedback records a rating on the answer the clicked buttons belong to.
+func (p *Pipeline) HandleFeedback(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	_, rating, _ := strings.Cut(i.MessageComponentData().CustomID, ":")
+	if p.feedback == nil {
+		return
+	}
+
+	run, ok := p.findExecution(i.Message.ID)
+	if !ok {
+		commands.RespondEphemeral(s, i, "This answer is too old to rate.")
+		return
+	}
+
+	user := commands.InteractionUser(i)
+	seenKey := "feedback:" + i.Message.ID + ":" + user.ID + ":" + rating
+	if !state.FirstSeen(seenKey, 24*time.Hour) {
+		commands.RespondEphemeral(s, i, "You already rated this answer.")
+		return
+	}
+
+	entry := feedback.Entry{
+		Rating:    rating,
+		UserID:    user.ID,
+		SessionID: run.conv.sessionID,
+		ThreadID:  run.conv.threadID,
+		MessageID: i.Message.ID,
+		Command:   run.workflow.Name,
+		Prompt:    run.conv.content,
+		Routed:    run.content,
+		Response:  run.response,
+	}
+	if err := p.feedback.Add(entry); err != nil {
+		log.Printf("Failed to save feedback: %v", err)
+		// Only a saved rating counts, so the user can try again.
+		state.Forget(seenKey)
+		commands.RespondEphemeral(s, i, "Sorry, I couldn't save your feedback.")
+		return
+	}
+
+	commands.RespondEphemeral(s, i, "Thanks for the feedback!")
+
+	if p.forwardFeedback {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_, err := p.n8n.TriggerWebhook(ctx, services.WebhookPayload{
+			Type:      "feedback",
+			Command:   run.workflow.Name,
+			Content:   run.response,
+			Message:   run.conv.content,
+			Rating:    rating,
+			UserID:    user.ID,
+			UserName:  user.Username,
+			ChannelID: i.ChannelID,
+			ThreadID:  run.conv.threadID,
+			SessionID: run.conv.sessionID,
+			MessageID: i.Message.ID,
+		})
+		if err != nil {
+			log.Printf("Failed to forward feedback: %v", err)
+		}
+	}
+}
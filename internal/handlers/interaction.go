@@ -1,32 +1,121 @@
 package handlers
 
 import (
+	"strings"
+	"time"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/marshall/zero-ops-bot/internal/commands"
+	"github.com/marshall/zero-ops-bot/internal/state"
 )
 
+// clickDebounce swallows repeated clicks on the same button.
+const clickDebounce = 3 * time.Second
+
 type InteractionHandlers struct {
 	Note     func(s *discordgo.Session, i *discordgo.InteractionCreate)
 	Schedule func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	SaveNote func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	AskBot   func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	Ask      func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	Feedback func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	Thread   func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	Todo     func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	Remind   func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	Session  func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	Context  func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	Settings func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	// Components and Modals map a custom ID prefix (the part before the
+	// first ":") to the handler for buttons, selects or modal submissions
+	// carrying it.
+	Components map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	Modals     map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate)
 }
 
 func NewInteractionHandler(h InteractionHandlers) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		if i.Type != discordgo.InteractionApplicationCommand {
+		if !state.FirstSeen("interaction:"+i.ID, dedupTTL) {
 			return
 		}
 
-		switch i.ApplicationCommandData().Name {
-		case "repo":
-			commands.HandleRepoCommand(s, i)
-		case "note":
-			if h.Note != nil {
-				h.Note(s, i)
+		switch i.Type {
+		case discordgo.InteractionApplicationCommand:
+			handleCommand(s, i, h)
+		case discordgo.InteractionMessageComponent:
+			customID := i.MessageComponentData().CustomID
+			// A double click is two interactions by the same user on the same
+			// message and button; other users' clicks go through.
+			if !state.FirstSeen("component:"+i.Message.ID+":"+commands.InteractionUser(i).ID+":"+customID, clickDebounce) {
+				s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+					Type: discordgo.InteractionResponseDeferredMessageUpdate,
+				})
+				return
 			}
-		case "schedule":
-			if h.Schedule != nil {
-				h.Schedule(s, i)
+
+			prefix, _, _ := strings.Cut(customID, ":")
+			if handler, ok := h.Components[prefix]; ok {
+				handler(s, i)
 			}
+		case discordgo.InteractionModalSubmit:
+			prefix, _, _ := strings.Cut(i.ModalSubmitData().CustomID, ":")
+			if handler, ok := h.Modals[prefix]; ok {
+				handler(s, i)
+			}
+		}
+	}
+}
+
+func handleCommand(s *discordgo.Session, i *discordgo.InteractionCreate, h InteractionHandlers) {
+	switch i.ApplicationCommandData().Name {
+	case "repo":
+		commands.HandleRepoCommand(s, i)
+	case "note":
+		if h.Note != nil {
+			h.Note(s, i)
+		}
+	case "schedule":
+		if h.Schedule != nil {
+			h.Schedule(s, i)
+		}
+	case "ask":
+		if h.Ask != nil {
+			h.Ask(s, i)
+		}
+	case "feedback":
+		if h.Feedback != nil {
+			h.Feedback(s, i)
+		}
+	case "todo":
+		if h.Todo != nil {
+			h.Todo(s, i)
+		}
+	case "remind":
+		if h.Remind != nil {
+			h.Remind(s, i)
+		}
+	case "session":
+		if h.Session != nil {
+			h.Session(s, i)
+		}
+	case "context":
+		if h.Context != nil {
+			h.Context(s, i)
+		}
+	case "settings":
+		if h.Settings != nil {
+			h.Settings(s, i)
+		}
+	case "thread":
+		if h.Thread != nil {
+			h.Thread(s, i)
+		}
+	case commands.SaveNoteCommandName:
+		if h.SaveNote != nil {
+			h.SaveNote(s, i)
+		}
+	case commands.AskBotCommandName:
+		if h.AskBot != nil {
+			h.AskBot(s, i)
 		}
 	}
 }
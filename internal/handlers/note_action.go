@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/commands"
+	"github.com/marshall/zero-ops-bot/internal/dates"
+	"github.com/marshall/zero-ops-bot/internal/notes"
+	"github.com/marshall/zero-ops-bot/internal/state"
+)
+
+// noteAction is the JSON the router writes as content for the note workflow.
+type noteAction struct {
+	Action   string    `json:"action"`
+	ID       string    `json:"id,omitempty"`
+	Text     string    `json:"text,omitempty"`
+	Category string    `json:"category,omitempty"`
+	Tags     []string  `json:"tags,omitempty"`
+	Date     string    `json:"date,omitempty"`
+	Query    string    `json:"query,omitempty"`
+	Mode     string    `json:"mode,omitempty"` // "semantic" searches by meaning
+	Index    noteIndex `json:"index,omitempty"`
+}
+
+// noteIndex is a 1-based position in a day's notes. Negative positions count
+// from the end, so -1 (or "last") is the latest note.
+type noteIndex int
+
+func (n *noteIndex) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var i int
+		if err := json.Unmarshal(data, &i); err != nil {
+			return fmt.Errorf("invalid note index %s", data)
+		}
+		*n = noteIndex(i)
+		return nil
+	}
+
+	switch s = strings.ToLower(strings.TrimSpace(s)); s {
+	case "last":
+		*n = -1
+	case "first":
+		*n = 1
+	default:
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid note index %q", s)
+		}
+		*n = noteIndex(i)
+	}
+	return nil
+}
+
+// noteConfirmation is a destructive note action waiting for the user to confirm.
+type noteConfirmation struct {
+	conv   *conversation
+	action noteAction
+}
+
+func handleNoteAction(s *discordgo.Session, c *conversation, content string, store *notes.Store) {
+	var action noteAction
+	if err := json.Unmarshal([]byte(content), &action); err != nil {
+		log.Printf("Failed to parse note action: %v", err)
+		c.fail(s, "Sorry, I couldn't understand the note request.")
+		return
+	}
+
+	// List takes ranges; everywhere else a date picks one daily file.
+	if action.Date != "" && action.Action != "list" {
+		day, err := dates.ParseDay(action.Date, store.Now(state.UserLocation(c.author.ID)))
+		if err != nil {
+			c.fail(s, err.Error())
+			return
+		}
+		action.Date = day.Format(dates.Layout)
+	}
+	if action.ID == "" && action.Date == "" && (action.Action == "remove" || action.Action == "move") {
+		action.Date = store.Today(state.UserLocation(c.author.ID))
+	}
+
+	switch action.Action {
+	case "add":
+		note, err := store.Add(notes.Note{
+			Text:     action.Text,
+			Category: action.Category,
+			Tags:     notes.ParseTags(strings.Join(action.Tags, ",")),
+			Author:   c.author.Username,
+		}, state.UserLocation(c.author.ID))
+		if err != nil {
+			c.fail(s, "Failed to save note: "+err.Error())
+			return
+		}
+
+		c.done(s)
+
+		label := "daily"
+		if note.Category != "" {
+			label = note.Category
+		}
+		c.reply(s, fmt.Sprintf("Got it, noted in **%s** (`%s`): %s", label, note.ID, note.Text))
+
+	case "today":
+		c.done(s)
+		c.reply(s, commands.NoteTodayMessage(store, state.UserLocation(c.author.ID)))
+
+	case "list":
+		c.done(s)
+		c.reply(s, commands.NoteListMessage(store, action.Date, action.Category, state.UserLocation(c.author.ID)))
+
+	case "search":
+		if action.Query == "" {
+			c.fail(s, "What should I search for?")
+			return
+		}
+		c.done(s)
+		c.reply(s, commands.NoteSearchMessage(store, action.Query, action.Mode == "semantic"))
+
+	case "edit":
+		if action.ID == "" || action.Text == "" {
+			c.fail(s, "Which note should I edit, and what should it say?")
+			return
+		}
+		c.done(s)
+		c.reply(s, commands.NoteEditMessage(store, action.ID, action.Text, c.author.Username))
+
+	case "remove", "move":
+		if action.Action == "move" && action.Category == "" {
+			c.fail(s, "Which category should the note move to?")
+			return
+		}
+		confirmNoteAction(s, c, action, store)
+
+	default:
+		c.fail(s, "Unknown note action: "+action.Action)
+	}
+}
+
+func confirmNoteAction(s *discordgo.Session, c *conversation, action noteAction, store *notes.Store) {
+	note, err := findActionNote(store, action)
+	if err != nil {
+		c.fail(s, fmt.Sprintf("Failed to %s note: %v", action.Action, err))
+		return
+	}
+	action.ID = note.ID
+
+	prompt := fmt.Sprintf("Remove this note from %s?\n> %s", note.Source(), note)
+	if action.Action == "move" {
+		prompt = fmt.Sprintf("Move this note from %s to **%s**?\n> %s", note.Source(), action.Category, note)
+	}
+
+	id := state.PutPending(&noteConfirmation{conv: c, action: action})
+	_, err = c.send(s, &discordgo.MessageSend{
+		Content: prompt,
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{Label: "Confirm", Style: discordgo.DangerButton, CustomID: "note:" + id + ":yes"},
+					discordgo.Button{Label: "Cancel", Style: discordgo.SecondaryButton, CustomID: "note:" + id + ":no"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to send note confirmation: %v", err)
+		state.TakePending(id)
+		c.fail(s, "Sorry, I couldn't ask for confirmation.")
+		return
+	}
+
+	c.setStatus(s, "❓")
+}
+
+// HandleNoteConfirmation runs or cancels a confirmed remove/move request.
+func (p *Pipeline) HandleNoteConfirmation(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	if len(parts) != 3 || p.notes == nil {
+		return
+	}
+
+	v, ok := state.PeekPending(parts[1])
+	if !ok {
+		commands.RespondEphemeral(s, i, "This request has expired.")
+		return
+	}
+	nc := v.(*noteConfirmation)
+
+	if commands.InteractionUser(i).ID != nc.conv.author.ID {
+		commands.RespondEphemeral(s, i, "Only the person who asked can confirm this.")
+		return
+	}
+
+	if _, ok := state.TakePending(parts[1]); !ok {
+		commands.RespondEphemeral(s, i, "This request has already been handled.")
+		return
+	}
+
+	if parts[2] != "yes" {
+		nc.conv.setStatus(s, "❌")
+		commands.UpdateMessage(s, i, "Cancelled.")
+		return
+	}
+
+	a := nc.action
+	var result string
+	if a.Action == "move" {
+		result = commands.NoteMoveMessage(p.notes, a.ID, a.Category, nc.conv.author.Username)
+	} else {
+		result = commands.NoteRemoveMessage(p.notes, a.ID, nc.conv.author.Username)
+	}
+
+	nc.conv.done(s)
+	commands.UpdateMessage(s, i, result)
+}
+
+// findActionNote resolves the note a remove/move action refers to: by ID, or
+// by its position in a daily file.
+func findActionNote(store *notes.Store, action noteAction) (notes.Note, error) {
+	if action.ID != "" {
+		return store.Get(action.ID)
+	}
+
+	daily, err := store.Daily(action.Date)
+	if err != nil {
+		return notes.Note{}, err
+	}
+	if action.Index == 0 {
+		return notes.Note{}, fmt.Errorf("which note? give its ID or position")
+	}
+	if len(daily) == 0 {
+		return notes.Note{}, fmt.Errorf("no notes on %s", action.Date)
+	}
+	pos := int(action.Index)
+	if pos < 0 {
+		pos += len(daily) + 1
+	}
+	if pos < 1 || pos > len(daily) {
+		return notes.Note{}, fmt.Errorf("note #%d not found on %s", action.Index, action.Date)
+	}
+	if daily[pos-1].ID != "" {
+		return daily[pos-1], nil
+	}
+
+	// Written outside the bot: it is about to change, so give it an ID now.
+	if _, err := store.Migrate(); err != nil {
+		return notes.Note{}, err
+	}
+	daily, err = store.Daily(action.Date)
+	if err != nil {
+		return notes.Note{}, err
+	}
+	if pos > len(daily) {
+		return notes.Note{}, fmt.Errorf("note #%d not found on %s", action.Index, action.Date)
+	}
+	return daily[pos-1], nil
+}
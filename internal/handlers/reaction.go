@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/commands"
+	"github.com/marshall/zero-ops-bot/internal/metadata"
+	"github.com/marshall/zero-ops-bot/internal/notes"
+	"github.com/marshall/zero-ops-bot/internal/state"
+)
+
+// NewReactionHandler runs the reaction triggers configured in metadata. With
+// none configured it ignores reactions.
+func NewReactionHandler(p *Pipeline) func(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	return func(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+		if r.UserID == s.State.User.ID || (r.Member != nil && r.Member.User != nil && r.Member.User.Bot) {
+			return
+		}
+
+		meta := metadata.Get()
+		trigger, ok := meta.FindReaction(r.Emoji.Name)
+		if !ok {
+			return
+		}
+
+		if !state.FirstSeen("reaction:"+r.MessageID+":"+r.UserID+":"+r.Emoji.Name, dedupTTL) {
+			return
+		}
+
+		if !hasAllowedRole(s, r.GuildID, r.Member, trigger.AllowedRoles) {
+			log.Printf("Ignoring %s reaction from %s: missing allowed role", r.Emoji.Name, r.UserID)
+			return
+		}
+
+		msg, err := s.ChannelMessage(r.ChannelID, r.MessageID)
+		if err != nil {
+			log.Printf("Failed to fetch reacted message: %v", err)
+			return
+		}
+
+		user := reactionUser(s, r)
+		log.Printf("Reaction %s (%s) by %s on message %s", r.Emoji.Name, trigger.Action, user.Username, r.MessageID)
+
+		switch trigger.Action {
+		case "note":
+			p.saveReactedNote(s, r, msg, user, trigger.Category)
+		case "ask":
+			p.askAboutMessage(s, r.GuildID, msg, user, r.Member)
+		case "rerun":
+			p.rerun(s, r, user)
+		}
+	}
+}
+
+// saveReactedNote files someone's message as a note with a link back to it.
+func (p *Pipeline) saveReactedNote(s *discordgo.Session, r *discordgo.MessageReactionAdd, msg *discordgo.Message, user *discordgo.User, category string) {
+	if p.notes == nil {
+		return
+	}
+
+	text := messageText(msg)
+	if text == "" {
+		return
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+	entry := fmt.Sprintf("%s — %s (%s)", text, msg.Author.Username, messageLink(r.GuildID, msg))
+	if _, err := p.notes.Add(notes.Note{Text: entry, Category: category, Author: user.Username}, state.UserLocation(user.ID)); err != nil {
+		log.Printf("Failed to save reacted note: %v", err)
+		s.MessageReactionAdd(r.ChannelID, r.MessageID, "❌")
+		return
+	}
+	s.MessageReactionAdd(r.ChannelID, r.MessageID, "✅")
+}
+
+// askAboutMessage opens a bot thread on msg and runs the pipeline with the
+// message as the subject, on behalf of the user who asked.
+func (p *Pipeline) askAboutMessage(s *discordgo.Session, guildID string, msg *discordgo.Message, user *discordgo.User, member *discordgo.Member) {
+	text := messageText(msg)
+	if text == "" {
+		return
+	}
+
+	s.MessageReactionAdd(msg.ChannelID, msg.ID, "👀")
+
+	threadID := msg.ChannelID
+	newThread := false
+	if channel, err := s.Channel(msg.ChannelID); err != nil || !channel.IsThread() {
+		if msg.Thread != nil {
+			threadID = msg.Thread.ID
+		} else {
+			thread, err := s.MessageThreadStart(msg.ChannelID, msg.ID, threadTitle(text), defaultArchiveMinutes)
+			if err != nil {
+				log.Printf("Failed to create thread: %v", err)
+				return
+			}
+			threadID = thread.ID
+			newThread = true
+		}
+	}
+	state.AddThread(threadID)
+
+	p.run(s, &conversation{
+		guildID:   guildID,
+		channelID: msg.ChannelID,
+		messageID: msg.ID,
+		threadID:  threadID,
+		sessionID: state.ThreadIDToSessionID(threadID),
+		author:    user,
+		member:    member,
+		content:   fmt.Sprintf("Analyze this message from %s:\n%s", msg.Author.Username, text),
+		title:     threadTitle(text),
+		newThread: newThread,
+		status:    "👀",
+		revision:  "ask-" + user.ID,
+	})
+}
+
+// HandleAskBotCommand runs the "Ask bot about this" message context menu.
+func (p *Pipeline) HandleAskBotCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	msg, ok := data.Resolved.Messages[data.TargetID]
+	if !ok {
+		commands.RespondEphemeral(s, i, "Couldn't find that message.")
+		return
+	}
+
+	commands.RespondEphemeral(s, i, "Looking into it…")
+	p.askAboutMessage(s, i.GuildID, msg, commands.InteractionUser(i), i.Member)
+}
+
+// rerun repeats the workflow run that produced a bot reply.
+func (p *Pipeline) rerun(s *discordgo.Session, r *discordgo.MessageReactionAdd, user *discordgo.User) {
+	run, ok := p.findExecution(r.MessageID)
+	if !ok {
+		return
+	}
+
+	if !hasAllowedRole(s, r.GuildID, r.Member, run.workflow.AllowedRoles) {
+		log.Printf("Ignoring rerun of %s by %s: missing allowed role", run.workflow.Name, r.UserID)
+		return
+	}
+
+	c := rerunConversation(run.conv, r, user)
+	c.setStatus(s, "👀")
+
+	ctx, cancel := context.WithTimeout(context.Background(), pipelineTimeout)
+	defer cancel()
+	p.runWorkflow(ctx, s, &c, run.workflow, run.content)
+}
+
+// rerunConversation turns the stored conversation of an execution into a
+// new one answering in the channel the reaction came from. Replies never go
+// to the original interaction, whose token expires after 15 minutes.
+func rerunConversation(c conversation, r *discordgo.MessageReactionAdd, user *discordgo.User) conversation {
+	c.channelID = r.ChannelID
+	c.threadID = r.ChannelID
+	c.messageID = r.MessageID
+	c.author = user
+	c.member = r.Member
+	c.status = ""
+	c.previous = nil
+	c.newThread = false
+	c.interaction = nil
+	c.answered = false
+	c.ephemeral = false
+	c.revision = strconv.FormatInt(time.Now().UnixMilli(), 10)
+	return c
+}
+
+func reactionUser(s *discordgo.Session, r *discordgo.MessageReactionAdd) *discordgo.User {
+	if r.Member != nil && r.Member.User != nil {
+		return r.Member.User
+	}
+	if u, err := s.User(r.UserID); err == nil {
+		return u
+	}
+	return &discordgo.User{ID: r.UserID}
+}
+
+// messageText returns a message's content, falling back to its embeds so
+// alert messages posted by integrations can be used too.
+func messageText(msg *discordgo.Message) string {
+	if text := strings.TrimSpace(msg.Content); text != "" {
+		return text
+	}
+
+	var parts []string
+	for _, e := range msg.Embeds {
+		for _, part := range []string{e.Title, e.Description} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func messageLink(guildID string, msg *discordgo.Message) string {
+	if guildID == "" {
+		guildID = "@me"
+	}
+	return fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, msg.ChannelID, msg.ID)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestRerunConversation_FromInteraction(t *testing.T) {
+	asked := conversation{
+		channelID:   "channel",
+		threadID:    "channel",
+		author:      &discordgo.User{ID: "asker"},
+		content:     "is the nas up?",
+		interaction: &discordgo.Interaction{ID: "interaction", Token: "expired"},
+		ephemeral:   true,
+		answered:    true,
+		previous:    []string{"old-reply"},
+	}
+	r := &discordgo.MessageReactionAdd{MessageReaction: &discordgo.MessageReaction{
+		UserID:    "reactor",
+		MessageID: "reply",
+		ChannelID: "thread",
+	}}
+
+	c := rerunConversation(asked, r, &discordgo.User{ID: "reactor"})
+
+	if c.interaction != nil || c.answered || c.ephemeral {
+		t.Errorf("Expected interaction state to be cleared, got interaction=%v answered=%v ephemeral=%v", c.interaction, c.answered, c.ephemeral)
+	}
+	if c.channelID != "thread" || c.threadID != "thread" {
+		t.Errorf("Expected replies in thread, got channel %q thread %q", c.channelID, c.threadID)
+	}
+	if len(c.previous) != 0 {
+		t.Errorf("Expected no previous replies, got %v", c.previous)
+	}
+	if c.author.ID != "reactor" {
+		t.Errorf("Expected author reactor, got %s", c.author.ID)
+	}
+	if key := c.idempotencyKey("chat"); !strings.HasPrefix(key, "reply") {
+		t.Errorf("Expected idempotency key from the reacted message, got %q", key)
+	}
+	if asked.interaction == nil {
+		t.Error("Expected the stored conversation to be left unchanged")
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/commands"
+	"github.com/marshall/zero-ops-bot/internal/reminders"
+)
+
+// remindAction is the JSON the router writes as content for the remind workflow.
+type remindAction struct {
+	When string `json:"when"`
+	Text string `json:"text"`
+	DM   bool   `json:"dm"`
+}
+
+func handleRemindAction(s *discordgo.Session, c *conversation, content string, manager *reminders.Manager) {
+	var action remindAction
+	if err := json.Unmarshal([]byte(content), &action); err != nil {
+		log.Printf("Failed to parse remind action: %v", err)
+		c.fail(s, "Sorry, I couldn't understand the reminder. Try `/remind`.")
+		return
+	}
+	if action.When == "" {
+		c.fail(s, "When should I remind you?")
+		return
+	}
+
+	msg, err := commands.RemindMessage(manager, c.author.ID, c.threadID, action.When, action.Text, action.DM)
+	if err != nil {
+		c.fail(s, msg)
+		return
+	}
+
+	c.done(s)
+	c.reply(s, msg)
+}
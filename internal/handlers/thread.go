@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/commands"
+	"github.com/marshall/zero-ops-bot/internal/notes"
+	"github.com/marshall/zero-ops-bot/internal/services"
+	"github.com/marshall/zero-ops-bot/internal/state"
+)
+
+const (
+	summaryCategory    = "incidents"
+	maxSummaryMessages = 500
+)
+
+// HandleThreadCommand runs /thread close in bot threads, for the person who
+// started the thread or a moderator.
+func (p *Pipeline) HandleThreadCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 || options[0].Name != "close" {
+		commands.RespondEphemeral(s, i, "No subcommand provided")
+		return
+	}
+
+	save := false
+	category := summaryCategory
+	for _, opt := range options[0].Options {
+		switch opt.Name {
+		case "save":
+			save = opt.BoolValue()
+		case "category":
+			category = opt.StringValue()
+		}
+	}
+
+	thread, err := s.Channel(i.ChannelID)
+	if err != nil || !thread.IsThread() {
+		commands.RespondEphemeral(s, i, "This command only works inside a thread.")
+		return
+	}
+	if !state.IsActiveThread(thread.ID) && thread.OwnerID != s.State.User.ID {
+		commands.RespondEphemeral(s, i, "This command only works in bot threads.")
+		return
+	}
+	if !canCloseThread(s, i, thread) {
+		commands.RespondEphemeral(s, i, "Only the person who started this thread or a moderator can close it.")
+		return
+	}
+
+	if !save {
+		commands.Respond(s, i, "Closing this thread.")
+		p.closeThread(s, thread)
+		return
+	}
+
+	if p.notes == nil {
+		commands.RespondEphemeral(s, i, "Notes are not configured.")
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), pipelineTimeout)
+	defer cancel()
+
+	summary, err := p.summarizeThread(ctx, s, thread, commands.InteractionUser(i))
+	if err == nil {
+		err = p.saveSummary(thread, summary, category, commands.InteractionUser(i))
+	}
+	if err != nil {
+		content := "Failed to summarize thread: " + err.Error()
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &content})
+		return
+	}
+
+	content := commands.Truncate(fmt.Sprintf("Saved summary to **%s**:\n%s", category, summary), 1900)
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &content})
+	p.closeThread(s, thread)
+}
+
+// canCloseThread allows the person who started the conversation and members
+// who can manage threads.
+func canCloseThread(s *discordgo.Session, i *discordgo.InteractionCreate, thread *discordgo.Channel) bool {
+	if i.Member != nil && i.Member.Permissions&discordgo.PermissionManageThreads != 0 {
+		return true
+	}
+	return threadOwner(s, thread) == commands.InteractionUser(i).ID
+}
+
+// threadOwner returns who started a conversation: the thread's owner or, for
+// threads the bot opened, the author of the message it was opened from (or
+// the user a bot forum post mentions).
+func threadOwner(s *discordgo.Session, thread *discordgo.Channel) string {
+	if thread.OwnerID != s.State.User.ID {
+		return thread.OwnerID
+	}
+	if msg, err := s.ChannelMessage(thread.ParentID, thread.ID); err == nil && msg.Author != nil {
+		return msg.Author.ID
+	}
+	if msg, err := s.ChannelMessage(thread.ID, thread.ID); err == nil && len(msg.Mentions) > 0 {
+		return msg.Mentions[0].ID
+	}
+	return ""
+}
+
+// NewThreadArchiveHandler summarizes bot threads into notes when Discord
+// auto-archives them.
+func NewThreadArchiveHandler(p *Pipeline) func(s *discordgo.Session, t *discordgo.ThreadUpdate) {
+	return func(s *discordgo.Session, t *discordgo.ThreadUpdate) {
+		if t.ThreadMetadata == nil || !t.ThreadMetadata.Archived || !state.IsActiveThread(t.ID) {
+			return
+		}
+		state.RemoveThread(t.ID)
+
+		if p.notes == nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), pipelineTimeout)
+		defer cancel()
+
+		summary, err := p.summarizeThread(ctx, s, t.Channel, s.State.User)
+		if err == nil {
+			err = p.saveSummary(t.Channel, summary, summaryCategory, s.State.User)
+		}
+		if err != nil {
+			log.Printf("Failed to summarize archived thread %s: %v", t.ID, err)
+			return
+		}
+		log.Printf("Saved summary of archived thread %s", t.ID)
+	}
+}
+
+func (p *Pipeline) closeThread(s *discordgo.Session, thread *discordgo.Channel) {
+	state.RemoveThread(thread.ID)
+
+	archived := true
+	if _, err := s.ChannelEdit(thread.ID, &discordgo.ChannelEdit{Archived: &archived}); err != nil {
+		log.Printf("Failed to archive thread %s: %v", thread.ID, err)
+	}
+}
+
+// summarizeThread asks n8n to summarize the thread's history.
+func (p *Pipeline) summarizeThread(ctx context.Context, s *discordgo.Session, thread *discordgo.Channel, user *discordgo.User) (string, error) {
+	transcript, err := threadTranscript(s, thread.ID)
+	if err != nil {
+		return "", fmt.Errorf("read thread: %w", err)
+	}
+	if transcript == "" {
+		return "", fmt.Errorf("thread is empty")
+	}
+
+	result, err := p.n8n.TriggerWebhook(ctx, services.WebhookPayload{
+		Type:    "summary",
+		Command: "summarize",
+		Content: "Summarize this troubleshooting conversation for future reference: the problem, " +
+			"what was tried, the root cause and the resolution. Be concise and use plain sentences.\n\n" +
+			transcript,
+		UserID:    user.ID,
+		UserName:  user.Username,
+		ChannelID: thread.ParentID,
+		ThreadID:  thread.ID,
+		SessionID: state.ThreadIDToSessionID(thread.ID),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	summary := strings.TrimSpace(result.Message)
+	if summary == "" {
+		return "", fmt.Errorf("empty summary")
+	}
+	return summary, nil
+}
+
+func (p *Pipeline) saveSummary(thread *discordgo.Channel, summary, category string, user *discordgo.User) error {
+	link := fmt.Sprintf("https://discord.com/channels/%s/%s", thread.GuildID, thread.ID)
+	_, err := p.notes.Add(notes.Note{
+		Text:     fmt.Sprintf("%s: %s (%s)", thread.Name, summary, link),
+		Category: category,
+		Author:   user.Username,
+		Tags:     []string{"summary"},
+	}, state.UserLocation(user.ID))
+	return err
+}
+
+// threadTranscript returns the thread's messages oldest first as
+// "author: content" lines.
+func threadTranscript(s *discordgo.Session, threadID string) (string, error) {
+	var messages []*discordgo.Message
+	before := ""
+	for len(messages) < maxSummaryMessages {
+		page, err := s.ChannelMessages(threadID, 100, before, "", "")
+		if err != nil {
+			return "", err
+		}
+		if len(page) == 0 {
+			break
+		}
+		messages = append(messages, page...)
+		before = page[len(page)-1].ID
+	}
+
+	slices.Reverse(messages)
+
+	var sb strings.Builder
+	for _, m := range messages {
+		text := messageText(m)
+		if text == "" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("[%s] %s: %s\n", m.Timestamp.Format(time.DateTime), m.Author.Username, text))
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/marshall/zero-ops-bot/internal/commands"
+	"github.com/marshall/zero-ops-bot/internal/metadata"
+)
+
+const (
+	defaultArchiveMinutes = 60
+	maxThreadTitle        = 80
+	maxThreadName         = 100
+)
+
+// archiveDurations are the auto-archive values Discord accepts.
+var archiveDurations = []int{60, 1440, 4320, 10080}
+
+// startThread opens a conversation for a channel message: a thread on the
+// message, or a post in the channel's configured forum.
+func startThread(s *discordgo.Session, m *discordgo.MessageCreate, cfg metadata.Channel, title, content string) (*discordgo.Channel, error) {
+	archive := cfg.ArchiveMinutes
+	if !slices.Contains(archiveDurations, archive) {
+		archive = defaultArchiveMinutes
+	}
+
+	if cfg.ForumChannelID == "" {
+		return s.MessageThreadStart(m.ChannelID, m.ID, title, archive)
+	}
+
+	link := fmt.Sprintf("https://discord.com/channels/%s/%s/%s", m.GuildID, m.ChannelID, m.ID)
+	starter := fmt.Sprintf("%s asked in <#%s> (%s):\n%s", m.Author.Mention(), m.ChannelID, link, content)
+	return s.ForumThreadStart(cfg.ForumChannelID, title, archive, commands.Truncate(starter, 1900))
+}
+
+// retitle renames a thread the bot opened for this conversation once the
+// routed command is known, and tags forum posts with the matching tag.
+func (c *conversation) retitle(s *discordgo.Session, command, routerTitle string) {
+	if !c.newThread || command == "reject" {
+		return
+	}
+	c.newThread = false
+
+	title := c.title
+	if routerTitle != "" {
+		title = threadTitle(routerTitle)
+	}
+
+	edit := &discordgo.ChannelEdit{
+		Name: commands.Truncate("["+command+"] "+title, maxThreadName),
+	}
+	if c.forumID != "" {
+		if tagID := forumTagFor(s, c.forumID, command); tagID != "" {
+			edit.AppliedTags = &[]string{tagID}
+		}
+	}
+
+	if _, err := s.ChannelEdit(c.threadID, edit); err != nil {
+		log.Printf("Failed to retitle thread %s: %v", c.threadID, err)
+	}
+}
+
+// threadTitle derives a thread name from the first line of a message.
+func threadTitle(content string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	line = strings.Join(strings.Fields(line), " ")
+	if line == "" {
+		return "Chat"
+	}
+	return commands.Truncate(line, maxThreadTitle)
+}
+
+// forumWorkflow returns the workflow mapped to one of a forum post's applied
+// tags, or "" when the thread isn't a tagged forum post. The tag only decides
+// the post's opening message (whose ID is the post's); follow-ups go through
+// the router like in any thread.
+func forumWorkflow(s *discordgo.Session, thread *discordgo.Channel, messageID string, meta metadata.Metadata) string {
+	if messageID != thread.ID || len(thread.AppliedTags) == 0 {
+		return ""
+	}
+
+	parent, err := s.Channel(thread.ParentID)
+	if err != nil || parent.Type != discordgo.ChannelTypeGuildForum {
+		return ""
+	}
+
+	tags := meta.FindChannel(parent.ID).ForumTags
+	for _, tag := range parent.AvailableTags {
+		if !slices.Contains(thread.AppliedTags, tag.ID) {
+			continue
+		}
+		if wf, ok := tags[tag.Name]; ok {
+			return wf
+		}
+	}
+	return ""
+}
+
+// forumTagFor finds the forum tag mapped to a workflow.
+func forumTagFor(s *discordgo.Session, forumID, command string) string {
+	forum, err := s.Channel(forumID)
+	if err != nil {
+		return ""
+	}
+
+	tags := metadata.Get().FindChannel(forumID).ForumTags
+	for _, tag := range forum.AvailableTags {
+		if tags[tag.Name] == command {
+			return tag.ID
+		}
+	}
+	return ""
+}
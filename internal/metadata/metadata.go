@@ -21,12 +21,126 @@ type Schedule struct {
 	Prompt       string `yaml:"prompt" json:"prompt"`
 	IncludeNotes bool   `yaml:"include_notes" json:"include_notes"`
 	IncludeRepos bool   `yaml:"include_repos" json:"include_repos"`
+	IncludeTodos bool   `yaml:"include_todos" json:"include_todos"`
+}
+
+// Route is a deterministic pre-router rule. A message matching Pattern (a
+// regular expression) or containing any of Keywords is sent straight to
+// Command without asking the LLM router. Content is a text/template rendered
+// with the message and the pattern's capture groups; empty means the message
+// is forwarded as-is.
+type Route struct {
+	Name     string   `yaml:"name" json:"name"`
+	Pattern  string   `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	Keywords []string `yaml:"keywords,omitempty" json:"keywords,omitempty"`
+	Command  string   `yaml:"command" json:"command"`
+	Content  string   `yaml:"content,omitempty" json:"content,omitempty"`
+}
+
+// Workflow is an entry in the catalog of commands the router may choose.
+// WebhookURL overrides the global N8N_WEBHOOK_URL for execution, and
+// AllowedRoles (role IDs or names) restricts who may run or approve it.
+type Workflow struct {
+	Name            string   `yaml:"name" json:"name"`
+	Description     string   `yaml:"description" json:"description"`
+	WebhookURL      string   `yaml:"webhook_url,omitempty" json:"webhook_url,omitempty"`
+	RequireApproval bool     `yaml:"require_approval,omitempty" json:"require_approval,omitempty"`
+	AllowedRoles    []string `yaml:"allowed_roles,omitempty" json:"allowed_roles,omitempty"`
+}
+
+// Channel holds per-channel conversation settings. ArchiveMinutes must be one
+// of Discord's auto-archive durations (60, 1440, 4320, 10080). Mentions in a
+// channel with ForumChannelID set start a post in that forum instead of a
+// thread. ForumTags, on a forum channel, maps tag names to workflows: the
+// opening message of a post carrying a mapped tag skips the router.
+type Channel struct {
+	ID             string            `yaml:"id" json:"id"`
+	ArchiveMinutes int               `yaml:"archive_minutes,omitempty" json:"archive_minutes,omitempty"`
+	ForumChannelID string            `yaml:"forum_channel_id,omitempty" json:"forum_channel_id,omitempty"`
+	ForumTags      map[string]string `yaml:"forum_tags,omitempty" json:"forum_tags,omitempty"`
+}
+
+// ReactionTrigger runs an action when someone reacts to a message with Emoji:
+// "note" saves the message to Category (default daily), "ask" starts a bot
+// thread about it and "rerun" repeats the workflow behind a bot reply.
+// AllowedRoles (role IDs or names) restricts who may trigger it. Triggers are
+// opt-in: without any configured, reactions do nothing.
+type ReactionTrigger struct {
+	Emoji        string   `yaml:"emoji" json:"emoji"`
+	Action       string   `yaml:"action" json:"action"`
+	Category     string   `yaml:"category,omitempty" json:"category,omitempty"`
+	AllowedRoles []string `yaml:"allowed_roles,omitempty" json:"allowed_roles,omitempty"`
 }
 
 type Metadata struct {
-	SystemPrompt string     `yaml:"system_prompt" json:"system_prompt"`
-	Schedules    []Schedule `yaml:"schedules" json:"schedules"`
-	Repos        []Repo     `yaml:"repos" json:"repos"`
+	SystemPrompt     string            `yaml:"system_prompt" json:"system_prompt"`
+	Workflows        []Workflow        `yaml:"workflows" json:"workflows"`
+	DefaultWorkflow  string            `yaml:"default_workflow,omitempty" json:"default_workflow,omitempty"`
+	ClarifyThreshold float64           `yaml:"clarify_threshold,omitempty" json:"clarify_threshold,omitempty"`
+	Routes           []Route           `yaml:"routes" json:"routes"`
+	Channels         []Channel         `yaml:"channels,omitempty" json:"channels,omitempty"`
+	Reactions        []ReactionTrigger `yaml:"reactions,omitempty" json:"reactions,omitempty"`
+	Schedules        []Schedule        `yaml:"schedules" json:"schedules"`
+	Repos            []Repo            `yaml:"repos" json:"repos"`
+}
+
+// Catalog returns the configured workflows, falling back to the built-in
+// set for metadata files written before the catalog existed.
+func (m Metadata) Catalog() []Workflow {
+	if len(m.Workflows) == 0 {
+		return defaultWorkflows
+	}
+	return m.Workflows
+}
+
+// WorkflowNames returns the names of the catalog's workflows.
+func (m Metadata) WorkflowNames() []string {
+	var names []string
+	for _, wf := range m.Catalog() {
+		names = append(names, wf.Name)
+	}
+	return names
+}
+
+// FindReaction returns the configured trigger for an emoji.
+func (m Metadata) FindReaction(emoji string) (ReactionTrigger, bool) {
+	for _, t := range m.Reactions {
+		if t.Emoji == emoji {
+			return t, true
+		}
+	}
+	return ReactionTrigger{}, false
+}
+
+// FindChannel returns the settings for a channel, or zero settings when the
+// channel isn't configured.
+func (m Metadata) FindChannel(id string) Channel {
+	for _, ch := range m.Channels {
+		if ch.ID == id {
+			return ch
+		}
+	}
+	return Channel{ID: id}
+}
+
+// FindWorkflow looks up a workflow in the catalog by name.
+func (m Metadata) FindWorkflow(name string) (Workflow, bool) {
+	for _, wf := range m.Catalog() {
+		if wf.Name == name {
+			return wf, true
+		}
+	}
+	return Workflow{}, false
+}
+
+// FindRepo looks up a registered repository by name.
+func (m Metadata) FindRepo(name string) (Repo, bool) {
+	for _, r := range m.Repos {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Repo{}, false
 }
 
 var (
@@ -44,9 +158,12 @@ func Load(path string) error {
 	file, err := os.ReadFile(path)
 	if os.IsNotExist(err) {
 		data = Metadata{
-			SystemPrompt: defaultSystemPrompt,
-			Schedules:    []Schedule{},
-			Repos:        []Repo{},
+			SystemPrompt:    defaultSystemPrompt,
+			Workflows:       defaultWorkflows,
+			DefaultWorkflow: "chat",
+			Routes:          []Route{},
+			Schedules:       []Schedule{},
+			Repos:           []Repo{},
 		}
 		return Save()
 	}
@@ -141,18 +258,25 @@ func ListSchedules() []Schedule {
 	return data.Schedules
 }
 
-const defaultSystemPrompt = `You are a homelab assistant. Classify the user's message and route it to the appropriate workflow.
+var defaultWorkflows = []Workflow{
+	{Name: "infra", Description: "Server infrastructure tasks (deploy, restart, status, logs, docker, kubectl)"},
+	{Name: "health", Description: "Health checks (uptime, disk, memory, CPU, connectivity)"},
+	{Name: "note", Description: "Remember, list, search, edit, remove or move notes. Content is a JSON note action"},
+	{Name: "remind", Description: "Set a one-time reminder (\"remind me in 2 hours to ...\"). Content is a JSON remind action"},
+	{Name: "chat", Description: "General conversation, questions, or anything that doesn't match above"},
+	{Name: "reject", Description: "Requests outside bot capabilities (weather, trivia, general knowledge, off-topic questions)"},
+}
 
-## Available Workflows
-- "infra" — Server infrastructure tasks (deploy, restart, status, logs, docker, kubectl)
-- "health" — Health checks (uptime, disk, memory, CPU, connectivity)
-- "note" — Remember or store information. Respond with JSON: {"command": "note", "content": "{\"action\":\"add\",\"text\":\"...\",\"category\":\"daily\"}"}
-- "chat" — General conversation, questions, or anything that doesn't match above
-- "reject" — Requests outside bot capabilities (weather, trivia, general knowledge, off-topic questions)
+const defaultSystemPrompt = `You are a homelab assistant. Classify the user's message and route it to the appropriate workflow.
+The available workflows are listed separately by the bot.
 
 ## Note Detection
 When the user says things like "remember ...", "note that ...", "don't forget ...", "save this ...", classify as "note".
 Extract the core information into "text" and pick an appropriate category (default "daily").
+Questions about what was noted ("what did I note about ...", "show yesterday's notes") and requests to delete or move notes are also "note".
+
+## Reminder Detection
+When the user asks to be reminded at a specific time ("remind me in 2 hours to ...", "ping me tomorrow at 9 about ..."), classify as "remind".
 
 ## Available Repositories
 Repositories are provided in the payload with name, description, and filesystem path. Use this context when the user references a project or codebase.
@@ -165,5 +289,5 @@ Repositories are provided in the payload with name, description, and filesystem
 
 ## Response Format
 Respond with JSON only:
-{"command": "<infra|health|note|chat|reject>", "content": "<routed prompt, JSON for note, or rejection message>"}
+{"command": "<workflow name>", "content": "<routed prompt, JSON for note or remind, or rejection message>"}
 `
@@ -0,0 +1,256 @@
+package notes
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// revertTrailer marks an undo commit with the change it reverted, so the
+// next undo goes one step further back instead of redoing.
+const revertTrailer = "Reverts: "
+
+// Change is one commit in the notes history.
+type Change struct {
+	Hash    string
+	Time    time.Time
+	Message string
+	reverts string
+}
+
+// EnableHistory makes NOTES_DIR a local git repository (no remote) and
+// commits after every change the store makes.
+func (s *Store) EnableHistory() error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("git not found: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(filepath.Join(s.baseDir, ".git")); os.IsNotExist(err) {
+		if _, err := s.git("init", "-q"); err != nil {
+			return err
+		}
+	}
+
+	if err := s.ignoreEmbeddings(); err != nil {
+		return err
+	}
+
+	s.history = true
+	return s.commitAll("Snapshot notes", "")
+}
+
+// ignoreEmbeddings adds the embeddings files to NOTES_DIR's .gitignore,
+// keeping any rules already there, and stops tracking a vectors file
+// committed before.
+func (s *Store) ignoreEmbeddings() error {
+	ignore := filepath.Join(s.baseDir, ".gitignore")
+	data, err := os.ReadFile(ignore)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	content := string(data)
+	existing := strings.Split(content, "\n")
+	for _, rule := range []string{embeddingsFile, embeddingsFile + ".tmp"} {
+		if slices.Contains(existing, rule) {
+			continue
+		}
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += rule + "\n"
+	}
+	if content != string(data) {
+		if err := os.WriteFile(ignore, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.git("rm", "-q", "--cached", "--ignore-unmatch", embeddingsFile)
+	return err
+}
+
+// HistoryEnabled reports whether changes are committed to git.
+func (s *Store) HistoryEnabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.history
+}
+
+// History returns the most recent changes, newest first.
+func (s *Store) History(limit int) ([]Change, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.history {
+		return nil, fmt.Errorf("note history is not enabled")
+	}
+	return s.log(limit)
+}
+
+// Undo reverts the most recent change that hasn't been undone yet and
+// returns it. Undoing again steps further back.
+func (s *Store) Undo(by string) (Change, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.history {
+		return Change{}, fmt.Errorf("note history is not enabled")
+	}
+	s.snapshot()
+
+	changes, err := s.log(0)
+	if err != nil {
+		return Change{}, err
+	}
+
+	undone := map[string]bool{}
+	for i, c := range changes {
+		switch {
+		case c.reverts != "":
+			undone[c.reverts] = true
+			continue
+		case undone[c.Hash]:
+			continue
+		case i == len(changes)-1:
+			// The first commit is the notes as they were when history
+			// was enabled; there's nothing before it to go back to.
+			return Change{}, fmt.Errorf("nothing to undo")
+		}
+
+		if _, err := s.git("revert", "--no-commit", c.Hash); err != nil {
+			s.git("revert", "--abort")
+			return Change{}, fmt.Errorf("can't undo %q, later changes depend on it", c.Message)
+		}
+		message := fmt.Sprintf("Undo: %s\n\n%s%s", c.Message, revertTrailer, c.Hash)
+		if err := s.commitAll(message, by); err != nil {
+			return Change{}, err
+		}
+		return c, nil
+	}
+	return Change{}, fmt.Errorf("nothing to undo")
+}
+
+// log reads the history, newest first; limit <= 0 means all of it.
+func (s *Store) log(limit int) ([]Change, error) {
+	args := []string{"log", "--format=%H%x1f%cI%x1f%s%x1f%b%x1e"}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("-n%d", limit))
+	}
+	out, err := s.git(args...)
+	if err != nil {
+		// A repository without commits has no log yet.
+		if _, headErr := s.git("rev-parse", "--verify", "-q", "HEAD"); headErr != nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var changes []Change
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.Split(strings.TrimSpace(record), "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+
+		c := Change{Hash: fields[0], Message: fields[2]}
+		c.Time, _ = time.Parse(time.RFC3339, fields[1])
+		for _, line := range strings.Split(fields[3], "\n") {
+			if hash, ok := strings.CutPrefix(strings.TrimSpace(line), revertTrailer); ok {
+				c.reverts = hash
+			}
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// snapshot commits edits made outside the bot, such as by n8n, so they
+// aren't folded into the next change. The caller holds s.mu.
+func (s *Store) snapshot() {
+	if !s.history {
+		return
+	}
+	if err := s.commitAll("External changes", ""); err != nil {
+		log.Printf("Failed to commit external note changes: %v", err)
+	}
+}
+
+// record commits a change the store just made. The change is already on
+// disk, so a failed commit is logged rather than returned. The caller holds
+// s.mu.
+func (s *Store) record(by, format string, args ...any) {
+	if !s.history {
+		return
+	}
+	if err := s.commitAll(fmt.Sprintf(format, args...), by); err != nil {
+		log.Printf("Failed to commit note change: %v", err)
+	}
+}
+
+// commitAll commits everything in NOTES_DIR, if anything changed.
+func (s *Store) commitAll(message, by string) error {
+	if _, err := s.git("add", "-A"); err != nil {
+		return err
+	}
+	if _, err := s.git("diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+
+	if by != "" {
+		subject, body, _ := strings.Cut(message, "\n")
+		message = subject + " (by " + by + ")"
+		if body != "" {
+			message += "\n" + body
+		}
+	}
+	_, err := s.git("commit", "-q", "--no-verify", "-m", message)
+	return err
+}
+
+func (s *Store) git(args ...string) (string, error) {
+	name := args[0]
+	args = append([]string{
+		"-c", "user.name=zero-ops-bot",
+		"-c", "user.email=zero-ops-bot@localhost",
+		"-c", "commit.gpgsign=false",
+	}, args...)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = s.baseDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", name, msg)
+		}
+		return "", fmt.Errorf("git %s: %w", name, err)
+	}
+	return string(out), nil
+}
+
+// summary shortens note text for a commit message.
+func summary(text string) string {
+	if r := []rune(text); len(r) > 60 {
+		return string(r[:60]) + "…"
+	}
+	return text
+}
+
+func kind(n Note) string {
+	if n.Todo {
+		return "todo"
+	}
+	return "note"
+}
@@ -0,0 +1,156 @@
+package notes
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func historyStore(t *testing.T) (*Store, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	store, err := NewStore(dir, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.EnableHistory(); err != nil {
+		t.Fatal(err)
+	}
+	return store, dir
+}
+
+func messages(t *testing.T, store *Store) []string {
+	t.Helper()
+	changes, err := store.History(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for _, c := range changes {
+		out = append(out, c.Message)
+	}
+	return out
+}
+
+func TestHistoryRecordsChanges(t *testing.T) {
+	store, dir := historyStore(t)
+
+	n, err := store.Add(Note{Text: "first", Author: "alice"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Remove(n.ID, "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := messages(t, store)
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 changes, got %q", got)
+	}
+	if !strings.HasPrefix(got[0], "Remove note "+n.ID) || !strings.HasSuffix(got[0], "(by bob)") {
+		t.Errorf("Unexpected remove commit %q", got[0])
+	}
+	if !strings.HasPrefix(got[1], "Add note "+n.ID) || !strings.HasSuffix(got[1], "(by alice)") {
+		t.Errorf("Unexpected add commit %q", got[1])
+	}
+
+	// Edits made outside the bot get their own commit.
+	path := filepath.Join(dir, "categories", "ops.md")
+	if err := os.WriteFile(path, []byte("# ops\n\n## 2024-01-01\n- 09:00 | written by n8n <!-- id=ext1 -->\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Edit("ext1", "edited", "carol"); err != nil {
+		t.Fatal(err)
+	}
+	got = messages(t, store)
+	if got[1] != "External changes" || !strings.HasPrefix(got[0], "Edit note ext1") {
+		t.Errorf("Expected external changes committed before the edit, got %q", got[:2])
+	}
+}
+
+func TestUndo(t *testing.T) {
+	store, _ := historyStore(t)
+
+	first, err := store.Add(Note{Text: "first"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := store.Add(Note{Text: "second"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Remove(first.ID, "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Undo brings back the removed note.
+	if _, err := store.Undo("bob"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(first.ID); err != nil {
+		t.Errorf("Expected the removed note to be restored, got %v", err)
+	}
+
+	// Undoing again steps back instead of redoing the removal.
+	if _, err := store.Undo("bob"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(second.ID); err == nil {
+		t.Error("Expected the second undo to revert adding the second note")
+	}
+	if _, err := store.Get(first.ID); err != nil {
+		t.Errorf("Expected the first note to still be there, got %v", err)
+	}
+
+	if _, err := store.Undo("bob"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Undo("bob"); err == nil {
+		t.Error("Expected nothing left to undo")
+	}
+}
+
+func TestHistoryIgnoresEmbeddingsWithExistingGitignore(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	store, err := NewStore(dir, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.swp"), 0644)
+	os.WriteFile(filepath.Join(dir, embeddingsFile), []byte("{}"), 0644)
+
+	if err := store.EnableHistory(); err != nil {
+		t.Fatal(err)
+	}
+
+	ignore, _ := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if !strings.HasPrefix(string(ignore), "*.swp\n") || !strings.Contains(string(ignore), "\n"+embeddingsFile+"\n") {
+		t.Errorf("Expected the embeddings rule appended to the existing rules, got %q", ignore)
+	}
+	tracked, err := store.git("ls-files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(tracked, embeddingsFile) {
+		t.Errorf("Expected %s not to be committed, got %q", embeddingsFile, tracked)
+	}
+
+	// Enabling again doesn't repeat the rules.
+	if err := store.EnableHistory(); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := os.ReadFile(filepath.Join(dir, ".gitignore")); string(again) != string(ignore) {
+		t.Errorf("Expected .gitignore unchanged, got %q", again)
+	}
+}
@@ -0,0 +1,111 @@
+package notes
+
+import (
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// index is an inverted index over every note, rebuilt whenever the files
+// change, including edits made outside the bot.
+type index struct {
+	fingerprint fingerprint
+	notes       map[string]Note
+	text        map[string]string         // normalized text, for phrase matching
+	postings    map[string]map[string]int // term → note ID → count
+	lengths     map[string]int
+}
+
+// fingerprint summarizes the note files cheaply enough to check on every
+// search.
+type fingerprint struct {
+	files    int
+	size     int64
+	modified time.Time
+}
+
+func fingerprintOf(files []noteFile) fingerprint {
+	fp := fingerprint{files: len(files)}
+	for _, f := range files {
+		info, err := os.Stat(f.path)
+		if err != nil {
+			continue
+		}
+		fp.size += info.Size()
+		if info.ModTime().After(fp.modified) {
+			fp.modified = info.ModTime()
+		}
+	}
+	return fp
+}
+
+// searchIndex returns an up-to-date index. The caller holds at least a read
+// lock on s.mu.
+func (s *Store) searchIndex() (*index, error) {
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+
+	fp := fingerprintOf(files)
+
+	s.idxMu.Lock()
+	defer s.idxMu.Unlock()
+
+	if s.idx != nil && s.idx.fingerprint == fp {
+		return s.idx, nil
+	}
+
+	idx := &index{
+		fingerprint: fp,
+		notes:       map[string]Note{},
+		text:        map[string]string{},
+		postings:    map[string]map[string]int{},
+		lengths:     map[string]int{},
+	}
+	for _, f := range files {
+		lines, err := readLines(f.path)
+		if err != nil {
+			continue
+		}
+		for _, nl := range noteLines(f, lines) {
+			idx.add(nl.note)
+		}
+	}
+
+	s.idx = idx
+	return idx, nil
+}
+
+func (idx *index) add(n Note) {
+	// Notes without an ID predate migration; index them under their position
+	// so they are still searchable.
+	key := n.ID
+	if key == "" {
+		key = n.File() + "#" + n.Time + n.Text
+	}
+
+	idx.notes[key] = n
+	idx.text[key] = normalizeText(n.Text)
+
+	terms := tokenize(n.Text + " " + n.Author + " " + strings.Join(n.Tags, " "))
+	idx.lengths[key] = len(terms)
+	for _, t := range terms {
+		if idx.postings[t] == nil {
+			idx.postings[t] = map[string]int{}
+		}
+		idx.postings[t][key]++
+	}
+}
+
+// tokenize splits text into lowercase words of letters and digits.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func normalizeText(text string) string {
+	return strings.Join(tokenize(text), " ")
+}
@@ -0,0 +1,193 @@
+package notes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Note is one entry of a daily or category file. On disk it stays a markdown
+// list item; the ID, author and tags live in a trailing HTML comment so the
+// rendered file reads as before, e.g.
+// "- 14:05 | restarted nginx <!-- id=3f9a1c2b author=marshall tags=ops -->".
+type Note struct {
+	ID       string
+	Date     string // YYYY-MM-DD
+	Time     string // HH:MM
+	Author   string
+	Tags     []string
+	Text     string
+	Category string // empty for daily notes
+
+	// Todos are stored as "- [ ] HH:MM | text" and "- [x] HH:MM | text".
+	Todo      bool
+	Done      bool
+	Due       string // YYYY-MM-DD, optional
+	Priority  string // high, medium or low, optional
+	Completed string // YYYY-MM-DD the todo was marked done
+}
+
+// Priorities a todo can have, most urgent first.
+var Priorities = []string{"high", "medium", "low"}
+
+const (
+	metaOpen  = " <!-- "
+	metaClose = " -->"
+)
+
+// Source names the file a note lives in: its category, or its date for daily
+// notes.
+func (n Note) Source() string {
+	if n.Category != "" {
+		return n.Category
+	}
+	return n.Date
+}
+
+// File is the note's file relative to the notes directory.
+func (n Note) File() string {
+	if n.Category != "" {
+		return "categories/" + n.Category + ".md"
+	}
+	return "daily/" + n.Date + ".md"
+}
+
+// Overdue reports whether an open todo's due date is before today.
+func (n Note) Overdue(today string) bool {
+	return n.Todo && !n.Done && n.Due != "" && n.Due < today
+}
+
+// String formats a note for chat replies.
+func (n Note) String() string {
+	var sb strings.Builder
+	if n.ID != "" {
+		fmt.Fprintf(&sb, "`%s` ", n.ID)
+	}
+	if n.Todo {
+		if n.Done {
+			sb.WriteString("☑ ")
+		} else {
+			sb.WriteString("☐ ")
+		}
+	}
+	fmt.Fprintf(&sb, "%s | %s", n.Time, n.Text)
+	if n.Due != "" {
+		sb.WriteString(" (due " + n.Due + ")")
+	}
+	if n.Priority != "" {
+		sb.WriteString(" [" + n.Priority + "]")
+	}
+	for _, tag := range n.Tags {
+		sb.WriteString(" #" + tag)
+	}
+	if n.Author != "" {
+		sb.WriteString(" — " + n.Author)
+	}
+	return sb.String()
+}
+
+// line renders the note as it is stored.
+func (n Note) line() string {
+	meta := []string{"id=" + n.ID}
+	if n.Author != "" {
+		meta = append(meta, "author="+n.Author)
+	}
+	if len(n.Tags) > 0 {
+		meta = append(meta, "tags="+strings.Join(n.Tags, ","))
+	}
+	if n.Due != "" {
+		meta = append(meta, "due="+n.Due)
+	}
+	if n.Priority != "" {
+		meta = append(meta, "priority="+n.Priority)
+	}
+	if n.Completed != "" {
+		meta = append(meta, "completed="+n.Completed)
+	}
+
+	box := ""
+	if n.Todo {
+		box = "[ ] "
+		if n.Done {
+			box = "[x] "
+		}
+	}
+	return fmt.Sprintf("- %s%s | %s%s%s%s", box, n.Time, n.Text, metaOpen, strings.Join(meta, " "), metaClose)
+}
+
+// parseNote reads a stored note line. Lines written before notes had IDs
+// parse with an empty ID.
+func parseNote(line string) (Note, bool) {
+	rest, ok := strings.CutPrefix(line, "- ")
+	if !ok {
+		return Note{}, false
+	}
+
+	var n Note
+	if box, after, ok := strings.Cut(rest, "] "); ok && (box == "[ " || box == "[x" || box == "[X") {
+		n.Todo = true
+		n.Done = box != "[ "
+		rest = after
+	}
+
+	if len(rest) < 8 || rest[2] != ':' || rest[5:8] != " | " {
+		return Note{}, false
+	}
+	n.Time, n.Text = rest[:5], rest[8:]
+
+	at := strings.LastIndex(n.Text, metaOpen)
+	if at == -1 || !strings.HasSuffix(n.Text, metaClose) {
+		return n, true
+	}
+	meta := n.Text[at+len(metaOpen) : len(n.Text)-len(metaClose)]
+	if !strings.HasPrefix(meta, "id=") {
+		return n, true
+	}
+
+	n.Text = n.Text[:at]
+	for _, field := range strings.Fields(meta) {
+		key, value, _ := strings.Cut(field, "=")
+		switch key {
+		case "id":
+			n.ID = value
+		case "author":
+			n.Author = value
+		case "tags":
+			n.Tags = strings.Split(value, ",")
+		case "due":
+			n.Due = value
+		case "priority":
+			n.Priority = value
+		case "completed":
+			n.Completed = value
+		}
+	}
+	return n, true
+}
+
+func newID() string {
+	return strings.ReplaceAll(uuid.NewString(), "-", "")[:8]
+}
+
+// cleanText keeps a note on one line so it can't forge list items.
+func cleanText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// cleanWord makes an author or tag safe to store in the metadata comment.
+func cleanWord(word string) string {
+	word = strings.Join(strings.Fields(word), "-")
+	return strings.Trim(strings.NewReplacer(",", "", "=", "", "-->", "").Replace(word), "#")
+}
+
+// ParseTags splits a comma or space separated tag list.
+func ParseTags(list string) []string {
+	var tags []string
+	for _, t := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if t = strings.ToLower(cleanWord(t)); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
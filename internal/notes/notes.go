@@ -1,10 +1,11 @@
 package notes
 
 import (
-	"bufio"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -12,10 +13,22 @@ import (
 
 type Store struct {
 	baseDir string
+	loc     *time.Location
 	mu      sync.RWMutex
+	history bool // commit every change to git, see EnableHistory
+	// idsChecked is the state of the files when every note last had an ID.
+	idsChecked fingerprint
+
+	idxMu sync.Mutex
+	idx   *index
+
+	semMu sync.Mutex
+	sem   *semanticIndex
 }
 
-func NewStore(baseDir string) (*Store, error) {
+// NewStore keeps notes under baseDir. loc decides which daily file "today"
+// is, unless a caller passes a user's own timezone.
+func NewStore(baseDir string, loc *time.Location) (*Store, error) {
 	for _, dir := range []string{
 		filepath.Join(baseDir, "daily"),
 		filepath.Join(baseDir, "categories"),
@@ -25,29 +38,59 @@ func NewStore(baseDir string) (*Store, error) {
 		}
 	}
 
-	return &Store{baseDir: baseDir}, nil
+	return &Store{baseDir: baseDir, loc: loc}, nil
+}
+
+// Now is the current time in loc, or in the store's timezone when loc is nil.
+func (s *Store) Now(loc *time.Location) time.Time {
+	if loc == nil {
+		loc = s.loc
+	}
+	return time.Now().In(loc)
+}
+
+// Today is the date of today's daily file in loc, or in the store's timezone
+// when loc is nil.
+func (s *Store) Today(loc *time.Location) string {
+	return s.Now(loc).Format("2006-01-02")
 }
 
 func (s *Store) BaseDir() string {
 	return s.baseDir
 }
 
-func (s *Store) Add(text, category string) error {
+// Add stores a note, filling in its ID, and its date and time in loc (nil
+// for the store's timezone). Notes without a category (or "daily") go to
+// that day's daily file.
+func (s *Store) Add(n Note, loc *time.Location) (Note, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.snapshot()
+
+	now := s.Now(loc)
+	n.ID = newID()
+	n.Date = now.Format("2006-01-02")
+	n.Time = now.Format("15:04")
+	n.Text = cleanText(n.Text)
+	n.Author = cleanWord(n.Author)
+	if n.Category == "daily" {
+		n.Category = ""
+	}
+	if n.Todo {
+		if err := checkTodo(n); err != nil {
+			return Note{}, err
+		}
+	}
 
-	now := time.Now()
-	timestamp := now.Format("15:04")
-	entry := fmt.Sprintf("- %s | %s\n", timestamp, text)
-
-	if category == "" || category == "daily" {
-		return s.appendDaily(now, entry)
+	if err := s.insert(n); err != nil {
+		return Note{}, err
 	}
-	return s.appendCategory(category, now, entry)
+	s.record(n.Author, "Add %s %s to %s: %s", kind(n), n.ID, n.Source(), summary(n.Text))
+	return n, nil
 }
 
-func (s *Store) GetToday() (string, error) {
-	return s.GetByDate(time.Now().Format("2006-01-02"))
+func (s *Store) GetToday(loc *time.Location) (string, error) {
+	return s.GetByDate(s.Today(loc))
 }
 
 func (s *Store) GetByDate(date string) (string, error) {
@@ -70,7 +113,7 @@ func (s *Store) GetRecent(days int) (string, error) {
 	defer s.mu.RUnlock()
 
 	var sb strings.Builder
-	now := time.Now()
+	now := s.Now(nil)
 
 	for i := 0; i < days; i++ {
 		date := now.AddDate(0, 0, -i).Format("2006-01-02")
@@ -123,94 +166,559 @@ func (s *Store) ListCategories() ([]string, error) {
 	return categories, nil
 }
 
-func (s *Store) Remove(date string, index int) error {
+// Daily returns the notes of a daily file.
+func (s *Store) Daily(date string) ([]Note, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.notesIn(s.dailyFile(date))
+}
+
+// Category returns the notes of a category file.
+func (s *Store) Category(category string) ([]Note, error) {
+	if err := checkCategory(category); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.notesIn(s.categoryFile(category))
+}
+
+// Get looks up a note by ID in daily and category files.
+func (s *Store) Get(id string) (Note, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, _, _, n, err := s.locate(id)
+	return n, err
+}
+
+// Remove deletes a note by ID and returns it. by names who asked, for the
+// history.
+func (s *Store) Remove(id, by string) (Note, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.ensureIDs()
+	s.snapshot()
 
-	path := filepath.Join(s.baseDir, "daily", date+".md")
-	data, err := os.ReadFile(path)
+	f, lines, at, n, err := s.locate(id)
+	if err != nil {
+		return Note{}, err
+	}
+	if err := writeLines(f.path, slices.Delete(lines, at, at+1)); err != nil {
+		return Note{}, err
+	}
+	s.record(by, "Remove %s %s from %s: %s", kind(n), n.ID, n.Source(), summary(n.Text))
+	return n, nil
+}
+
+// Edit replaces a note's text, keeping its ID, time, author and tags.
+func (s *Store) Edit(id, text, by string) (Note, error) {
+	text = cleanText(text)
+	if text == "" {
+		return Note{}, fmt.Errorf("note text is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ensureIDs()
+	s.snapshot()
+
+	f, lines, at, n, err := s.locate(id)
+	if err != nil {
+		return Note{}, err
+	}
+
+	n.Text = text
+	lines[at] = n.line()
+	if err := writeLines(f.path, lines); err != nil {
+		return Note{}, err
+	}
+	s.record(by, "Edit %s %s: %s", kind(n), n.ID, summary(n.Text))
+	return n, nil
+}
+
+// Complete marks a todo as done.
+func (s *Store) Complete(id, by string) (Note, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ensureIDs()
+	s.snapshot()
+
+	f, lines, at, n, err := s.locate(id)
+	if err != nil {
+		return Note{}, err
+	}
+	if !n.Todo {
+		return Note{}, fmt.Errorf("note %s is not a todo", id)
+	}
+	if n.Done {
+		return Note{}, fmt.Errorf("todo %s is already done", id)
+	}
+
+	n.Done = true
+	n.Completed = s.Today(nil)
+	lines[at] = n.line()
+	if err := writeLines(f.path, lines); err != nil {
+		return Note{}, err
+	}
+	s.record(by, "Complete todo %s: %s", n.ID, summary(n.Text))
+	return n, nil
+}
+
+// Todos returns every todo, open or done, in file order.
+func (s *Store) Todos() ([]Note, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var todos []Note
+	for _, f := range files {
+		lines, err := readLines(f.path)
+		if err != nil {
+			continue
+		}
+		for _, nl := range noteLines(f, lines) {
+			if nl.note.Todo {
+				todos = append(todos, nl.note)
+			}
+		}
+	}
+	return todos, nil
+}
+
+// OpenTodos returns the todos not yet done, soonest due first, then by
+// priority. Todos without a due date come last.
+func (s *Store) OpenTodos() ([]Note, error) {
+	todos, err := s.Todos()
 	if err != nil {
+		return nil, err
+	}
+
+	open := slices.DeleteFunc(todos, func(n Note) bool { return n.Done })
+	slices.SortStableFunc(open, func(a, b Note) int {
+		if a.Due != b.Due {
+			switch {
+			case a.Due == "":
+				return 1
+			case b.Due == "":
+				return -1
+			}
+			return strings.Compare(a.Due, b.Due)
+		}
+		return priorityRank(a.Priority) - priorityRank(b.Priority)
+	})
+	return open, nil
+}
+
+func priorityRank(priority string) int {
+	if i := slices.Index(Priorities, priority); i != -1 {
+		return i
+	}
+	return len(Priorities)
+}
+
+func checkTodo(n Note) error {
+	if n.Due != "" {
+		if _, err := time.Parse("2006-01-02", n.Due); err != nil {
+			return fmt.Errorf("invalid due date %q, use YYYY-MM-DD", n.Due)
+		}
+	}
+	if n.Priority != "" && !slices.Contains(Priorities, n.Priority) {
+		return fmt.Errorf("invalid priority %q, use %s", n.Priority, strings.Join(Priorities, ", "))
+	}
+	return nil
+}
+
+// Move files a note under category, or back into its daily file when
+// category is empty or "daily", keeping its original date and time.
+func (s *Store) Move(id, category, by string) (Note, error) {
+	if category == "daily" {
+		category = ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ensureIDs()
+	s.snapshot()
+
+	f, lines, at, n, err := s.locate(id)
+	if err != nil {
+		return Note{}, err
+	}
+	if n.Category == category {
+		return Note{}, fmt.Errorf("note %s is already in %s", id, n.Source())
+	}
+
+	from := n.Source()
+	n.Category = category
+	if err := s.insert(n); err != nil {
+		return Note{}, err
+	}
+	if err := writeLines(f.path, slices.Delete(lines, at, at+1)); err != nil {
+		return Note{}, err
+	}
+	s.record(by, "Move %s %s from %s to %s", kind(n), n.ID, from, n.Source())
+	return n, nil
+}
+
+// RenameCategory renames a category file, keeping its notes.
+func (s *Store) RenameCategory(from, to, by string) error {
+	for _, name := range []string{from, to} {
+		if err := checkCategory(name); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshot()
+
+	src, dst := s.categoryFile(from), s.categoryFile(to)
+	lines, err := readLines(src.path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("category %s not found", from)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dst.path); err == nil {
+		return fmt.Errorf("category %s already exists, merge instead", to)
+	}
+
+	if len(lines) > 0 && lines[0] == "# "+from {
+		lines[0] = "# " + to
+	}
+	if err := writeLines(dst.path, lines); err != nil {
 		return err
 	}
+	if err := os.Remove(src.path); err != nil {
+		return err
+	}
+	s.record(by, "Rename category %s to %s", from, to)
+	return nil
+}
+
+// MergeCategory moves every note of from into into, under their original
+// dates, and deletes from. It returns how many notes moved.
+func (s *Store) MergeCategory(from, into, by string) (int, error) {
+	for _, name := range []string{from, into} {
+		if err := checkCategory(name); err != nil {
+			return 0, err
+		}
+	}
+	if from == into {
+		return 0, fmt.Errorf("cannot merge a category into itself")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshot()
+
+	src := s.categoryFile(from)
+	if _, err := os.Stat(src.path); os.IsNotExist(err) {
+		return 0, fmt.Errorf("category %s not found", from)
+	}
+	moved, err := s.notesIn(src)
+	if err != nil {
+		return 0, err
+	}
+
+	// appendCategory inserts right below the date header, so go backwards to
+	// keep each day's order.
+	for i := len(moved) - 1; i >= 0; i-- {
+		n := moved[i]
+		n.Category = into
+		if err := s.insert(n); err != nil {
+			return 0, err
+		}
+	}
+	if err := os.Remove(src.path); err != nil {
+		return 0, err
+	}
+	s.record(by, "Merge category %s into %s (%d notes)", from, into, len(moved))
+	return len(moved), nil
+}
+
+// DeleteCategory removes a category file and returns how many notes it held.
+func (s *Store) DeleteCategory(category, by string) (int, error) {
+	if err := checkCategory(category); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshot()
+
+	f := s.categoryFile(category)
+	list, err := s.notesIn(f)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Remove(f.path); os.IsNotExist(err) {
+		return 0, fmt.Errorf("category %s not found", category)
+	} else if err != nil {
+		return 0, err
+	}
+	s.record(by, "Delete category %s (%d notes)", category, len(list))
+	return len(list), nil
+}
+
+// RemoveDays deletes the daily files of the given dates and returns how many
+// notes they held. Category notes from those days are kept.
+func (s *Store) RemoveDays(dates []string, by string) (int, error) {
+	if len(dates) == 0 {
+		return 0, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshot()
+
+	removed := 0
+	for _, date := range dates {
+		f := s.dailyFile(date)
+		list, err := s.notesIn(f)
+		if err != nil {
+			return removed, err
+		}
+		if list == nil {
+			continue
+		}
+		if err := os.Remove(f.path); err != nil {
+			return removed, err
+		}
+		removed += len(list)
+	}
+
+	if removed > 0 {
+		span := dates[0]
+		if len(dates) > 1 {
+			span += " to " + dates[len(dates)-1]
+		}
+		s.record(by, "Remove %d daily notes from %s", removed, span)
+	}
+	return removed, nil
+}
+
+// checkCategory rejects names that can't be used as a category file.
+func checkCategory(category string) error {
+	if category == "" || category == "daily" {
+		return fmt.Errorf("category name is required")
+	}
+	if strings.ContainsAny(category, `/\`) || strings.HasPrefix(category, ".") {
+		return fmt.Errorf("invalid category name %q", category)
+	}
+	return nil
+}
+
+// Migrate gives an ID to every note written before notes had one and
+// returns how many were updated.
+func (s *Store) Migrate() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.assignIDs()
+}
+
+// ensureIDs gives IDs to notes written outside the bot, by n8n or by hand,
+// before a change, so they can be removed, edited and moved like any other.
+// Reads never rewrite files; until the next change such notes are listed
+// without an ID. It only looks at the files again after they change. The
+// caller holds s.mu.
+func (s *Store) ensureIDs() {
+	files, err := s.files()
+	if err != nil {
+		return
+	}
+	if fingerprintOf(files) == s.idsChecked {
+		return
+	}
+	if _, err := s.assignIDs(); err != nil {
+		log.Printf("Failed to assign note IDs: %v", err)
+		return
+	}
+	if files, err := s.files(); err == nil {
+		s.idsChecked = fingerprintOf(files)
+	}
+}
 
-	lines := strings.Split(string(data), "\n")
-	noteIndex := 0
-	removed := false
+// assignIDs writes an ID into every note line without one and returns how
+// many it assigned. The caller holds s.mu.
+func (s *Store) assignIDs() (int, error) {
+	files, err := s.files()
+	if err != nil {
+		return 0, err
+	}
 
-	var result []string
-	for _, line := range lines {
-		if strings.HasPrefix(line, "- ") {
-			noteIndex++
-			if noteIndex == index {
-				removed = true
+	changed := map[noteFile][]string{}
+	assigned := 0
+	for _, f := range files {
+		lines, err := readLines(f.path)
+		if err != nil {
+			return 0, err
+		}
+		for i, line := range lines {
+			n, ok := parseNote(line)
+			if !ok || n.ID != "" {
 				continue
 			}
+			n.ID = newID()
+			lines[i] = n.line()
+			changed[f] = lines
+			assigned++
 		}
-		result = append(result, line)
+	}
+	if assigned == 0 {
+		return 0, nil
 	}
 
-	if !removed {
-		return fmt.Errorf("note #%d not found", index)
+	// Commit the new notes as they were written before adding IDs to them.
+	s.snapshot()
+	for f, lines := range changed {
+		if err := writeLines(f.path, lines); err != nil {
+			return 0, err
+		}
 	}
+	s.record("", "Assign IDs to %d notes", assigned)
+	return assigned, nil
+}
 
-	return os.WriteFile(path, []byte(strings.Join(result, "\n")), 0644)
+// noteFile is a daily file (category empty) or a category file.
+type noteFile struct {
+	path     string
+	category string
+	date     string
 }
 
-func (s *Store) Search(query string) ([]string, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+func (s *Store) dailyFile(date string) noteFile {
+	return noteFile{path: filepath.Join(s.baseDir, "daily", date+".md"), date: date}
+}
 
-	query = strings.ToLower(query)
-	var results []string
+func (s *Store) categoryFile(category string) noteFile {
+	return noteFile{path: filepath.Join(s.baseDir, "categories", category+".md"), category: category}
+}
 
+// files lists every daily and category file.
+func (s *Store) files() ([]noteFile, error) {
+	var files []noteFile
 	for _, dir := range []string{"daily", "categories"} {
-		dirPath := filepath.Join(s.baseDir, dir)
-		entries, err := os.ReadDir(dirPath)
+		entries, err := os.ReadDir(filepath.Join(s.baseDir, dir))
 		if err != nil {
-			continue
+			return nil, err
 		}
-
 		for _, e := range entries {
 			if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") {
 				continue
 			}
-
-			path := filepath.Join(dirPath, e.Name())
-			matches, err := searchFile(path, query)
-			if err != nil {
-				continue
+			name := strings.TrimSuffix(e.Name(), ".md")
+			if dir == "daily" {
+				files = append(files, s.dailyFile(name))
+			} else {
+				files = append(files, s.categoryFile(name))
 			}
+		}
+	}
+	return files, nil
+}
 
-			source := strings.TrimSuffix(e.Name(), ".md")
-			for _, m := range matches {
-				results = append(results, fmt.Sprintf("[%s] %s", source, m))
+// notesIn parses the notes of a file; a missing file has none.
+func (s *Store) notesIn(f noteFile) ([]Note, error) {
+	lines, err := readLines(f.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []Note
+	for _, at := range noteLines(f, lines) {
+		notes = append(notes, at.note)
+	}
+	return notes, nil
+}
+
+type noteLine struct {
+	note Note
+	at   int
+}
+
+// noteLines finds the notes in a file's lines. Category notes take their
+// date from the nearest "## YYYY-MM-DD" header above them.
+func noteLines(f noteFile, lines []string) []noteLine {
+	var found []noteLine
+	date := f.date
+	for i, line := range lines {
+		if header, ok := strings.CutPrefix(line, "## "); ok && f.category != "" {
+			date = strings.TrimSpace(header)
+			continue
+		}
+		n, ok := parseNote(line)
+		if !ok {
+			continue
+		}
+		n.Date = date
+		n.Category = f.category
+		found = append(found, noteLine{note: n, at: i})
+	}
+	return found
+}
+
+// locate finds a note by ID and returns its file, the file's lines and the
+// note's line index.
+func (s *Store) locate(id string) (noteFile, []string, int, Note, error) {
+	id = strings.Trim(strings.TrimSpace(id), "`")
+	if id == "" {
+		return noteFile{}, nil, 0, Note{}, fmt.Errorf("note ID is required")
+	}
+
+	files, err := s.files()
+	if err != nil {
+		return noteFile{}, nil, 0, Note{}, err
+	}
+
+	for _, f := range files {
+		lines, err := readLines(f.path)
+		if err != nil {
+			continue
+		}
+		for _, nl := range noteLines(f, lines) {
+			if nl.note.ID == id {
+				return f, lines, nl.at, nl.note, nil
 			}
 		}
 	}
+	return noteFile{}, nil, 0, Note{}, fmt.Errorf("note %s not found", id)
+}
 
-	return results, nil
+// insert writes a note into its daily or category file.
+func (s *Store) insert(n Note) error {
+	if n.Category == "" {
+		return s.appendDaily(n.Date, n.line()+"\n")
+	}
+	if err := checkCategory(n.Category); err != nil {
+		return err
+	}
+	return s.appendCategory(n.Category, n.Date, n.line()+"\n")
 }
 
-func searchFile(path, query string) ([]string, error) {
-	f, err := os.Open(path)
+func readLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
+	return strings.Split(string(data), "\n"), nil
+}
 
-	var matches []string
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, "- ") && strings.Contains(strings.ToLower(line), query) {
-			matches = append(matches, line)
-		}
-	}
-	return matches, scanner.Err()
+func writeLines(path string, lines []string) error {
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
 }
 
-func (s *Store) appendDaily(date time.Time, entry string) error {
-	dateStr := date.Format("2006-01-02")
+func (s *Store) appendDaily(dateStr, entry string) error {
 	path := filepath.Join(s.baseDir, "daily", dateStr+".md")
 
 	if _, err := os.Stat(path); os.IsNotExist(err) {
@@ -228,9 +736,8 @@ func (s *Store) appendDaily(date time.Time, entry string) error {
 	return err
 }
 
-func (s *Store) appendCategory(category string, date time.Time, entry string) error {
+func (s *Store) appendCategory(category, dateStr, entry string) error {
 	path := filepath.Join(s.baseDir, "categories", category+".md")
-	dateStr := date.Format("2006-01-02")
 
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		content := fmt.Sprintf("# %s\n\n## %s\n%s", category, dateStr, entry)
@@ -0,0 +1,395 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNoteLineRoundTrip(t *testing.T) {
+	n := Note{ID: "3f9a1c2b", Time: "14:05", Author: "marshall", Tags: []string{"ops", "nginx"}, Text: "restarted nginx <b>now</b>"}
+
+	got, ok := parseNote(n.line())
+	if !ok {
+		t.Fatalf("Expected %q to parse", n.line())
+	}
+	if got.ID != n.ID || got.Time != n.Time || got.Author != n.Author || got.Text != n.Text || strings.Join(got.Tags, ",") != "ops,nginx" {
+		t.Errorf("Round trip mismatch: got %+v, want %+v", got, n)
+	}
+}
+
+func TestParseNote(t *testing.T) {
+	tests := []struct {
+		line string
+		ok   bool
+		id   string
+		text string
+	}{
+		{"- 09:30 | legacy note", true, "", "legacy note"},
+		{"- 09:30 | text <!-- user comment -->", true, "", "text <!-- user comment -->"},
+		{"- 09:30 | text <!-- id=abcd1234 -->", true, "abcd1234", "text"},
+		{"- [ ] 09:30 | open todo <!-- id=abcd1234 due=2024-01-01 -->", true, "abcd1234", "open todo"},
+		{"- [x] 09:30 | done todo <!-- id=abcd1234 -->", true, "abcd1234", "done todo"},
+		{"- [ ] not a note", false, "", ""},
+		{"- not a note", false, "", ""},
+		{"# 2024-01-01", false, "", ""},
+		{"", false, "", ""},
+	}
+
+	for _, tt := range tests {
+		n, ok := parseNote(tt.line)
+		if ok != tt.ok || n.ID != tt.id || n.Text != tt.text {
+			t.Errorf("parseNote(%q) = %+v, %v; want id=%q text=%q ok=%v", tt.line, n, ok, tt.id, tt.text, tt.ok)
+		}
+	}
+}
+
+func TestAddKeepsNotesOnOneLine(t *testing.T) {
+	store, err := NewStore(t.TempDir(), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := store.Add(Note{Text: "first line\n- 10:00 | forged", Author: "some user"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	daily, err := store.Daily(n.Date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(daily) != 1 {
+		t.Fatalf("Expected 1 note, got %d: %+v", len(daily), daily)
+	}
+	if daily[0].ID != n.ID || daily[0].Author != "some-user" {
+		t.Errorf("Unexpected note %+v", daily[0])
+	}
+}
+
+func TestRemoveEditMoveByID(t *testing.T) {
+	store, err := NewStore(t.TempDir(), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, _ := store.Add(Note{Text: "first"}, nil)
+	second, _ := store.Add(Note{Text: "second", Category: "ops"}, nil)
+	third, _ := store.Add(Note{Text: "third"}, nil)
+
+	if _, err := store.Remove(first.ID, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(first.ID); err == nil {
+		t.Error("Expected removed note to be gone")
+	}
+	if n, err := store.Get(third.ID); err != nil || n.Text != "third" {
+		t.Errorf("Expected third note to keep its ID after a removal, got %+v, %v", n, err)
+	}
+
+	if _, err := store.Edit(second.ID, "second, edited", "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := store.Get(second.ID); n.Text != "second, edited" || n.Category != "ops" {
+		t.Errorf("Unexpected edited note %+v", n)
+	}
+
+	if _, err := store.Move(third.ID, "ops", "tester"); err != nil {
+		t.Fatal(err)
+	}
+	ops, _ := store.Category("ops")
+	if len(ops) != 2 {
+		t.Errorf("Expected 2 notes in ops, got %+v", ops)
+	}
+
+	if _, err := store.Move(second.ID, "daily", "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := store.Get(second.ID); n.Category != "" || n.Date != second.Date {
+		t.Errorf("Expected note back in its daily file, got %+v", n)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	daily := "# 2024-01-02\n\n- 09:00 | one\n- 10:00 | two\n"
+	category := "# ops\n\n## 2024-01-01\n- 08:00 | deploy\n"
+	os.WriteFile(filepath.Join(dir, "daily", "2024-01-02.md"), []byte(daily), 0644)
+	os.WriteFile(filepath.Join(dir, "categories", "ops.md"), []byte(category), 0644)
+
+	migrated, err := store.Migrate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if migrated != 3 {
+		t.Errorf("Expected 3 migrated notes, got %d", migrated)
+	}
+
+	ops, _ := store.Category("ops")
+	if len(ops) != 1 || ops[0].ID == "" || ops[0].Date != "2024-01-01" || ops[0].Text != "deploy" {
+		t.Errorf("Unexpected migrated category note %+v", ops)
+	}
+
+	if again, _ := store.Migrate(); again != 0 {
+		t.Errorf("Expected migration to be idempotent, migrated %d again", again)
+	}
+}
+
+func TestExternalNotesGetIDs(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, _ := store.Add(Note{Text: "from the bot"}, nil)
+
+	// n8n appends to the same file without an ID.
+	path := filepath.Join(dir, "daily", n.Date+".md")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("- 23:59 | from n8n\n")
+	f.Close()
+	before, _ := os.ReadFile(path)
+
+	list, err := store.Daily(n.Date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[1].ID != "" {
+		t.Fatalf("Expected the external note to be listed without an ID, got %+v", list)
+	}
+	if _, err := store.Search(ParseQuery("n8n")); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Errorf("Expected reads to leave the file alone, got %q", after)
+	}
+
+	if _, err := store.Edit(n.ID, "edited by the bot", "tester"); err != nil {
+		t.Fatal(err)
+	}
+	list, _ = store.Daily(n.Date)
+	if len(list) != 2 || list[1].ID == "" {
+		t.Fatalf("Expected the external note to get an ID on the next change, got %+v", list)
+	}
+	if _, err := store.Remove(list[1].ID, "tester"); err != nil {
+		t.Errorf("Expected the external note to be removable by ID, got %v", err)
+	}
+}
+
+func TestCategoryManagement(t *testing.T) {
+	store, err := NewStore(t.TempDir(), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := store.Add(Note{Text: "a", Category: "infra"}, nil)
+	b, _ := store.Add(Note{Text: "b", Category: "infra"}, nil)
+	store.Add(Note{Text: "c", Category: "ops"}, nil)
+
+	if err := store.RenameCategory("infra", "ops", "tester"); err == nil {
+		t.Error("Expected rename onto an existing category to fail")
+	}
+	if err := store.RenameCategory("infra", "platform", "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := store.Get(a.ID); n.Category != "platform" {
+		t.Errorf("Expected renamed category, got %+v", n)
+	}
+
+	moved, err := store.MergeCategory("platform", "ops", "tester")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved != 2 {
+		t.Errorf("Expected 2 merged notes, got %d", moved)
+	}
+	ops, _ := store.Category("ops")
+	if len(ops) != 3 {
+		t.Errorf("Expected 3 notes in ops, got %+v", ops)
+	}
+	if n, _ := store.Get(b.ID); n.Category != "ops" {
+		t.Errorf("Expected merged note in ops, got %+v", n)
+	}
+
+	deleted, err := store.DeleteCategory("ops", "tester")
+	if err != nil || deleted != 3 {
+		t.Errorf("Expected 3 deleted notes, got %d, %v", deleted, err)
+	}
+	if categories, _ := store.ListCategories(); len(categories) != 0 {
+		t.Errorf("Expected no categories left, got %v", categories)
+	}
+
+	empty, _ := store.Add(Note{Text: "last one", Category: "scratch"}, nil)
+	store.Remove(empty.ID, "tester")
+	if moved, err := store.MergeCategory("scratch", "ops", "tester"); err != nil || moved != 0 {
+		t.Errorf("Expected an empty category to merge with 0 notes, got %d, %v", moved, err)
+	}
+	if categories, _ := store.ListCategories(); len(categories) != 0 {
+		t.Errorf("Expected the empty category to be removed, got %v", categories)
+	}
+	if _, err := store.MergeCategory("missing", "ops", "tester"); err == nil {
+		t.Error("Expected merging a missing category to fail")
+	}
+
+	if _, err := store.Add(Note{Text: "x", Category: "../escape"}, nil); err == nil {
+		t.Error("Expected a path-like category to be rejected")
+	}
+
+	daily, _ := store.Add(Note{Text: "keep me"}, nil)
+	if _, err := store.DeleteCategory("../daily/"+daily.Date, "tester"); err == nil {
+		t.Error("Expected deleting a path-like category to be rejected")
+	}
+	if err := store.RenameCategory("../daily/"+daily.Date, "stolen", "tester"); err == nil {
+		t.Error("Expected renaming a path-like category to be rejected")
+	}
+	if _, err := store.MergeCategory("../../escape", "ops", "tester"); err == nil {
+		t.Error("Expected merging a path-like category to be rejected")
+	}
+	if _, err := store.Category("../daily/" + daily.Date); err == nil {
+		t.Error("Expected reading a path-like category to be rejected")
+	}
+	if _, err := store.Get(daily.ID); err != nil {
+		t.Errorf("Expected the daily note to survive, got %v", err)
+	}
+}
+
+func TestTodos(t *testing.T) {
+	store, err := NewStore(t.TempDir(), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	later, _ := store.Add(Note{Text: "renew certs", Todo: true, Due: "2099-01-02"}, nil)
+	soon, _ := store.Add(Note{Text: "patch kernel", Todo: true, Due: "2099-01-01", Priority: "low"}, nil)
+	urgent, _ := store.Add(Note{Text: "rotate keys", Todo: true, Due: "2099-01-01", Priority: "high"}, nil)
+	someday, _ := store.Add(Note{Text: "clean garage", Todo: true}, nil)
+	store.Add(Note{Text: "plain note"}, nil)
+
+	if _, err := store.Add(Note{Text: "bad", Todo: true, Due: "tomorrow"}, nil); err == nil {
+		t.Error("Expected an invalid due date to be rejected")
+	}
+
+	open, err := store.OpenTodos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	for _, n := range open {
+		order = append(order, n.ID)
+	}
+	want := []string{urgent.ID, soon.ID, later.ID, someday.ID}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected open todo order %v, want %v", order, want)
+	}
+
+	done, err := store.Complete(soon.ID, "tester")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done.Done || done.Completed == "" {
+		t.Errorf("Expected completed todo, got %+v", done)
+	}
+	if _, err := store.Complete(soon.ID, "tester"); err == nil {
+		t.Error("Expected completing twice to fail")
+	}
+
+	if n, _ := store.Get(soon.ID); !n.Done || n.Due != "2099-01-01" || n.Priority != "low" {
+		t.Errorf("Expected todo fields to round trip, got %+v", n)
+	}
+	if open, _ := store.OpenTodos(); len(open) != 3 {
+		t.Errorf("Expected 3 open todos, got %d", len(open))
+	}
+
+	overdue := Note{Todo: true, Due: "2024-01-01"}
+	if !overdue.Overdue("2024-01-02") || overdue.Overdue("2024-01-01") {
+		t.Error("Unexpected Overdue result")
+	}
+}
+
+func TestAddUsesTimezone(t *testing.T) {
+	// 26 hours apart, so these zones never share a date.
+	west, err := time.LoadLocation("Etc/GMT+12")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	east, err := time.LoadLocation("Etc/GMT-14")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+
+	store, err := NewStore(t.TempDir(), west)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byStore, err := store.Add(Note{Text: "store zone"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byUser, err := store.Add(Note{Text: "user zone"}, east)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if byStore.Date != store.Today(nil) {
+		t.Errorf("Expected the store zone note dated %s, got %s", store.Today(nil), byStore.Date)
+	}
+	if byUser.Date != store.Today(east) {
+		t.Errorf("Expected the user zone note dated %s, got %s", store.Today(east), byUser.Date)
+	}
+	if byStore.Date == byUser.Date {
+		t.Errorf("Expected the notes on different days, both dated %s", byStore.Date)
+	}
+
+	list, err := store.Daily(store.Today(east))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ID != byUser.ID {
+		t.Errorf("Expected only %s in the user's daily file, got %v", byUser.ID, list)
+	}
+}
+
+func TestRemoveDays(t *testing.T) {
+	store, err := NewStore(t.TempDir(), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	today := store.Today(nil)
+	if _, err := store.Add(Note{Text: "daily one"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Add(Note{Text: "daily two"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	kept, err := store.Add(Note{Text: "filed", Category: "ops"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := store.RemoveDays([]string{"2020-01-01", today}, "tester")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 removed notes, got %d", removed)
+	}
+	if list, _ := store.Daily(today); len(list) != 0 {
+		t.Errorf("Expected no daily notes left, got %v", list)
+	}
+	if _, err := store.Get(kept.ID); err != nil {
+		t.Errorf("Expected the category note to be kept, got %v", err)
+	}
+}
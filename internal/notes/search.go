@@ -0,0 +1,275 @@
+package notes
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultPageSize is how many results a search page holds.
+const DefaultPageSize = 10
+
+// Query is a note search. Every term and phrase must match; filters narrow
+// the candidates further.
+type Query struct {
+	Terms    []string
+	Phrases  []string
+	Tags     []string
+	Category string // "daily" for daily notes only
+	From     string // YYYY-MM-DD, inclusive
+	To       string // YYYY-MM-DD, inclusive
+	Page     int    // 0-based
+	PageSize int
+	// Semantic ranks by meaning through the embeddings endpoint instead of
+	// matching words.
+	Semantic bool
+}
+
+// SearchResult is one page of ranked matches.
+type SearchResult struct {
+	Notes []Note
+	Total int
+	Page  int
+	Pages int
+}
+
+// ParseQuery reads a free-text query. Quoted text is a phrase; tag:x or #x,
+// category:x (or in:x), from:YYYY-MM-DD and to:YYYY-MM-DD are filters, and
+// mode:semantic searches by meaning.
+func ParseQuery(input string) Query {
+	var q Query
+
+	for i, part := range strings.Split(input, `"`) {
+		if i%2 == 1 {
+			if phrase := normalizeText(part); phrase != "" {
+				q.Phrases = append(q.Phrases, phrase)
+			}
+			continue
+		}
+
+		for _, word := range strings.Fields(part) {
+			key, value, ok := strings.Cut(word, ":")
+			switch {
+			case strings.HasPrefix(word, "#") && len(word) > 1:
+				q.Tags = append(q.Tags, ParseTags(word)...)
+			case ok && value != "" && key == "tag":
+				q.Tags = append(q.Tags, ParseTags(value)...)
+			case ok && value != "" && (key == "category" || key == "in"):
+				q.Category = value
+			case ok && value != "" && key == "from":
+				q.From = value
+			case ok && value != "" && key == "to":
+				q.To = value
+			case ok && value != "" && key == "mode":
+				q.Semantic = value == "semantic"
+			default:
+				q.Terms = append(q.Terms, tokenize(word)...)
+			}
+		}
+	}
+	return q
+}
+
+// Empty reports whether the query has nothing to search for or filter by.
+func (q Query) Empty() bool {
+	return len(q.Terms) == 0 && len(q.Phrases) == 0 && len(q.Tags) == 0 &&
+		q.Category == "" && q.From == "" && q.To == ""
+}
+
+// String renders the query back in ParseQuery syntax.
+func (q Query) String() string {
+	parts := slices.Clone(q.Terms)
+	for _, p := range q.Phrases {
+		parts = append(parts, `"`+p+`"`)
+	}
+	for _, t := range q.Tags {
+		parts = append(parts, "#"+t)
+	}
+	if q.Category != "" {
+		parts = append(parts, "in:"+q.Category)
+	}
+	if q.From != "" {
+		parts = append(parts, "from:"+q.From)
+	}
+	if q.To != "" {
+		parts = append(parts, "to:"+q.To)
+	}
+	if q.Semantic {
+		parts = append(parts, "mode:semantic")
+	}
+	return strings.Join(parts, " ")
+}
+
+// Words is the query's terms and phrases as plain text, what a semantic
+// search embeds.
+func (q Query) Words() string {
+	return strings.Join(append(slices.Clone(q.Terms), q.Phrases...), " ")
+}
+
+func (q Query) validate() error {
+	if q.Empty() {
+		return fmt.Errorf("search query is empty")
+	}
+	for _, date := range []string{q.From, q.To} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("invalid date %q, use YYYY-MM-DD", date)
+		}
+	}
+	return nil
+}
+
+// Search ranks the notes matching q and returns the requested page. Terms
+// also match words they are a prefix of, at a lower weight.
+func (s *Store) Search(q Query) (SearchResult, error) {
+	if err := q.validate(); err != nil {
+		return SearchResult{}, err
+	}
+	if q.Semantic {
+		return s.semanticSearch(q)
+	}
+
+	s.mu.RLock()
+	idx, err := s.searchIndex()
+	s.mu.RUnlock()
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	var hits []hit
+
+	for key, score := range idx.match(q) {
+		n := idx.notes[key]
+		if q.matchesFilters(n) {
+			hits = append(hits, hit{note: n, score: score})
+		}
+	}
+
+	sortHits(hits)
+	return page(hits, q), nil
+}
+
+type hit struct {
+	note  Note
+	score float64
+}
+
+func sortHits(hits []hit) {
+	slices.SortFunc(hits, func(a, b hit) int {
+		if a.score != b.score {
+			if a.score > b.score {
+				return -1
+			}
+			return 1
+		}
+		// Newest first among equals.
+		return strings.Compare(b.note.Date+b.note.Time, a.note.Date+a.note.Time)
+	})
+}
+
+// page cuts the page q asks for out of the ranked hits.
+func page(hits []hit, q Query) SearchResult {
+	if q.PageSize <= 0 {
+		q.PageSize = DefaultPageSize
+	}
+
+	result := SearchResult{Total: len(hits), Page: q.Page}
+	result.Pages = (len(hits) + q.PageSize - 1) / q.PageSize
+	start := min(q.Page*q.PageSize, len(hits))
+	end := min(start+q.PageSize, len(hits))
+	for _, h := range hits[start:end] {
+		result.Notes = append(result.Notes, h.note)
+	}
+	return result
+}
+
+func (q Query) matchesFilters(n Note) bool {
+	switch q.Category {
+	case "":
+	case "daily":
+		if n.Category != "" {
+			return false
+		}
+	default:
+		if !strings.EqualFold(n.Category, q.Category) {
+			return false
+		}
+	}
+
+	if q.From != "" && n.Date < q.From {
+		return false
+	}
+	if q.To != "" && n.Date > q.To {
+		return false
+	}
+
+	for _, tag := range q.Tags {
+		if !slices.Contains(n.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// match scores the notes containing every term and phrase of q: a TF-IDF
+// sum over the terms plus a bonus per phrase. Without terms every note is a
+// candidate, so filter-only queries work.
+func (idx *index) match(q Query) map[string]float64 {
+	scores := map[string]float64{}
+	if len(q.Terms) == 0 {
+		for key := range idx.notes {
+			scores[key] = 0
+		}
+	}
+
+	total := float64(len(idx.notes))
+	for i, term := range q.Terms {
+		best := map[string]float64{}
+		for word, postings := range idx.postings {
+			weight := 0.0
+			switch {
+			case word == term:
+				weight = 1
+			case strings.HasPrefix(word, term):
+				weight = 0.5
+			default:
+				continue
+			}
+
+			idf := math.Log(1 + total/float64(len(postings)))
+			for key, count := range postings {
+				length := float64(max(idx.lengths[key], 1))
+				if s := weight * float64(count) / length * idf; s > best[key] {
+					best[key] = s
+				}
+			}
+		}
+
+		if i == 0 {
+			scores = best
+			continue
+		}
+		for key := range scores {
+			if s, ok := best[key]; ok {
+				scores[key] += s
+			} else {
+				delete(scores, key)
+			}
+		}
+	}
+
+	for _, phrase := range q.Phrases {
+		for key := range scores {
+			if strings.Contains(" "+idx.text[key]+" ", " "+phrase+" ") {
+				scores[key]++
+			} else {
+				delete(scores, key)
+			}
+		}
+	}
+	return scores
+}
@@ -0,0 +1,124 @@
+package notes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	q := ParseQuery(`nginx "disk full" #ops tag:K8s in:infra from:2024-01-01 to:2024-02-01`)
+	want := Query{
+		Terms:    []string{"nginx"},
+		Phrases:  []string{"disk full"},
+		Tags:     []string{"ops", "k8s"},
+		Category: "infra",
+		From:     "2024-01-01",
+		To:       "2024-02-01",
+	}
+	if !reflect.DeepEqual(q, want) {
+		t.Errorf("ParseQuery = %+v, want %+v", q, want)
+	}
+}
+
+func searchStore(t *testing.T) (*Store, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := NewStore(dir, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"daily/2024-01-02.md": "# 2024-01-02\n\n" +
+			"- 09:00 | nginx restarted after disk full <!-- id=n1 tags=ops -->\n" +
+			"- 10:00 | lunch with the team <!-- id=n2 -->\n",
+		"daily/2024-02-10.md": "# 2024-02-10\n\n" +
+			"- 11:00 | disk is full again on nas <!-- id=n3 tags=ops,nas -->\n",
+		"categories/infra.md": "# infra\n\n## 2024-01-15\n" +
+			"- 08:00 | nginx config reload via nginx -s reload, nginx docs <!-- id=n4 -->\n" +
+			"- 08:30 | deployment pipeline notes <!-- id=n5 -->\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store, dir
+}
+
+func ids(notes []Note) []string {
+	var out []string
+	for _, n := range notes {
+		out = append(out, n.ID)
+	}
+	return out
+}
+
+func TestSearch(t *testing.T) {
+	store, _ := searchStore(t)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"nginx", []string{"n4", "n1"}},
+		{"nginx disk", []string{"n1"}},
+		{`"disk full"`, []string{"n1"}},
+		{"disk", []string{"n1", "n3"}},
+		{"deploy", []string{"n5"}},
+		{"disk #nas", []string{"n3"}},
+		{"nginx in:daily", []string{"n1"}},
+		{"nginx in:infra", []string{"n4"}},
+		{"disk from:2024-02-01", []string{"n3"}},
+		{"#ops to:2024-01-31", []string{"n1"}},
+		{"kubernetes", nil},
+	}
+
+	for _, tt := range tests {
+		result, err := store.Search(ParseQuery(tt.query))
+		if err != nil {
+			t.Errorf("Search(%q) returned error: %v", tt.query, err)
+			continue
+		}
+		if got := ids(result.Notes); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if _, err := store.Search(ParseQuery("  ")); err == nil {
+		t.Error("Expected an empty query to fail")
+	}
+	if _, err := store.Search(ParseQuery("x from:yesterday")); err == nil {
+		t.Error("Expected an invalid date to fail")
+	}
+}
+
+func TestSearchPaginationAndRefresh(t *testing.T) {
+	store, err := NewStore(t.TempDir(), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 25 {
+		store.Add(Note{Text: fmt.Sprintf("backup run %d", i)}, nil)
+	}
+
+	q := ParseQuery("backup")
+	q.Page = 2
+	result, err := store.Search(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 25 || result.Pages != 3 || len(result.Notes) != 5 {
+		t.Errorf("Unexpected page: total=%d pages=%d notes=%d", result.Total, result.Pages, len(result.Notes))
+	}
+
+	added, _ := store.Add(Note{Text: "restore drill"}, nil)
+	result, _ = store.Search(ParseQuery("restore"))
+	if got := ids(result.Notes); !reflect.DeepEqual(got, []string{added.ID}) {
+		t.Errorf("Expected the index to pick up a new note, got %v", got)
+	}
+}
@@ -0,0 +1,288 @@
+package notes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// embeddingsFile sits in NOTES_DIR next to daily/ and categories/.
+	embeddingsFile = ".embeddings.json"
+	embedBatchSize = 32
+	// maxSemanticResults caps a semantic search: every note has some
+	// similarity to the query, so past this the results are noise.
+	maxSemanticResults = 30
+	semanticTimeout    = 2 * time.Minute
+)
+
+// Embedder turns texts into vectors, one per text and in the same order.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	Model() string
+}
+
+// semanticIndex holds a vector per note, persisted so only new or changed
+// notes are embedded again.
+type semanticIndex struct {
+	mu       sync.Mutex
+	embedder Embedder
+	path     string
+	model    string
+	vectors  map[string]vectorEntry
+	queries  map[string][]float32
+}
+
+type vectorEntry struct {
+	Hash   string    `json:"hash"`
+	Vector []float32 `json:"vector"`
+}
+
+type embeddingsData struct {
+	Model   string                 `json:"model"`
+	Vectors map[string]vectorEntry `json:"vectors"`
+}
+
+// EnableSemantic turns on semantic search through e, loading the vectors
+// saved by earlier runs. Vectors from a different model are discarded.
+func (s *Store) EnableSemantic(e Embedder) error {
+	sem := &semanticIndex{
+		embedder: e,
+		path:     filepath.Join(s.baseDir, embeddingsFile),
+		model:    e.Model(),
+		vectors:  map[string]vectorEntry{},
+		queries:  map[string][]float32{},
+	}
+
+	data, err := os.ReadFile(sem.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(data) > 0 {
+		var saved embeddingsData
+		if err := json.Unmarshal(data, &saved); err != nil {
+			return fmt.Errorf("parse %s: %w", sem.path, err)
+		}
+		if saved.Model == sem.model && saved.Vectors != nil {
+			sem.vectors = saved.Vectors
+		}
+	}
+
+	s.semMu.Lock()
+	s.sem = sem
+	s.semMu.Unlock()
+	return nil
+}
+
+// SemanticEnabled reports whether an embeddings endpoint is configured.
+func (s *Store) SemanticEnabled() bool {
+	s.semMu.Lock()
+	defer s.semMu.Unlock()
+	return s.sem != nil
+}
+
+func (s *Store) semantic() *semanticIndex {
+	s.semMu.Lock()
+	defer s.semMu.Unlock()
+	return s.sem
+}
+
+// SyncEmbeddings embeds the notes that are new or changed since the last sync
+// and forgets deleted ones. It returns how many notes were embedded.
+func (s *Store) SyncEmbeddings(ctx context.Context) (int, error) {
+	sem := s.semantic()
+	if sem == nil {
+		return 0, fmt.Errorf("semantic search is not configured")
+	}
+
+	s.mu.RLock()
+	idx, err := s.searchIndex()
+	s.mu.RUnlock()
+	if err != nil {
+		return 0, err
+	}
+
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+	return sem.sync(ctx, idx)
+}
+
+// sync brings the vectors in line with idx; the caller holds sem.mu.
+func (sem *semanticIndex) sync(ctx context.Context, idx *index) (int, error) {
+	var stale []string
+	hashes := map[string]string{}
+	for key, n := range idx.notes {
+		if n.ID == "" {
+			continue
+		}
+		hashes[key] = embeddingHash(n)
+		if sem.vectors[key].Hash != hashes[key] {
+			stale = append(stale, key)
+		}
+	}
+	slices.Sort(stale)
+
+	changed := false
+	for key := range sem.vectors {
+		if _, ok := hashes[key]; !ok {
+			delete(sem.vectors, key)
+			changed = true
+		}
+	}
+
+	embedded := 0
+	var syncErr error
+	for start := 0; start < len(stale); start += embedBatchSize {
+		batch := stale[start:min(start+embedBatchSize, len(stale))]
+		texts := make([]string, len(batch))
+		for i, key := range batch {
+			texts[i] = embeddingText(idx.notes[key])
+		}
+
+		vectors, err := sem.embedder.Embed(ctx, texts)
+		if err == nil && len(vectors) != len(batch) {
+			err = fmt.Errorf("embeddings endpoint returned %d vectors for %d notes", len(vectors), len(batch))
+		}
+		if err != nil {
+			// Keep what was embedded so far; the rest is retried next sync.
+			syncErr = fmt.Errorf("embed notes: %w", err)
+			break
+		}
+
+		for i, key := range batch {
+			sem.vectors[key] = vectorEntry{Hash: hashes[key], Vector: vectors[i]}
+		}
+		embedded += len(batch)
+		changed = true
+	}
+
+	if changed {
+		if err := sem.save(); err != nil && syncErr == nil {
+			syncErr = err
+		}
+	}
+	return embedded, syncErr
+}
+
+// queryVector embeds a search query, reusing it for later pages.
+func (sem *semanticIndex) queryVector(ctx context.Context, text string) ([]float32, error) {
+	if v, ok := sem.queries[text]; ok {
+		return v, nil
+	}
+
+	vectors, err := sem.embedder.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("embeddings endpoint returned %d vectors for the query", len(vectors))
+	}
+
+	if len(sem.queries) >= 64 {
+		clear(sem.queries)
+	}
+	sem.queries[text] = vectors[0]
+	return vectors[0], nil
+}
+
+// semanticSearch ranks the notes passing q's filters by how close they are
+// in meaning to its words.
+func (s *Store) semanticSearch(q Query) (SearchResult, error) {
+	sem := s.semantic()
+	if sem == nil {
+		return SearchResult{}, fmt.Errorf("semantic search is not configured")
+	}
+	text := q.Words()
+	if text == "" {
+		return SearchResult{}, fmt.Errorf("semantic search needs words to search for")
+	}
+
+	s.mu.RLock()
+	idx, err := s.searchIndex()
+	s.mu.RUnlock()
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), semanticTimeout)
+	defer cancel()
+
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+
+	if _, err := sem.sync(ctx, idx); err != nil {
+		return SearchResult{}, err
+	}
+	query, err := sem.queryVector(ctx, text)
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	var hits []hit
+	for key, entry := range sem.vectors {
+		n, ok := idx.notes[key]
+		if ok && q.matchesFilters(n) {
+			hits = append(hits, hit{note: n, score: cosine(query, entry.Vector)})
+		}
+	}
+	sortHits(hits)
+	if len(hits) > maxSemanticResults {
+		hits = hits[:maxSemanticResults]
+	}
+	return page(hits, q), nil
+}
+
+func (sem *semanticIndex) save() error {
+	data, err := json.Marshal(embeddingsData{Model: sem.model, Vectors: sem.vectors})
+	if err != nil {
+		return err
+	}
+
+	tmp := sem.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, sem.path)
+}
+
+// embeddingText is what gets embedded for a note: its text, category and tags.
+func embeddingText(n Note) string {
+	parts := []string{n.Text}
+	if n.Category != "" {
+		parts = append(parts, "category: "+n.Category)
+	}
+	if len(n.Tags) > 0 {
+		parts = append(parts, "tags: "+strings.Join(n.Tags, ", "))
+	}
+	return strings.Join(parts, "\n")
+}
+
+func embeddingHash(n Note) string {
+	sum := sha256.Sum256([]byte(embeddingText(n)))
+	return hex.EncodeToString(sum[:8])
+}
+
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
@@ -0,0 +1,153 @@
+package notes
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeEmbedder maps words onto a few topics so related words land close
+// together, the way a real model would.
+type fakeEmbedder struct {
+	model string
+	calls int
+	texts int
+}
+
+var fakeTopics = []map[string]bool{
+	{"ups": true, "battery": true, "apc": true, "replacement": true, "power": true},
+	{"disk": true, "full": true, "nas": true, "storage": true},
+	{"nginx": true, "config": true, "reload": true, "web": true},
+	{"lunch": true, "team": true, "food": true},
+	{"deployment": true, "pipeline": true},
+}
+
+func (f *fakeEmbedder) Model() string { return f.model }
+
+func (f *fakeEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	f.calls++
+	f.texts += len(texts)
+
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		v := make([]float32, len(fakeTopics)+1)
+		v[len(fakeTopics)] = 0.1 // keep unrelated text off the zero vector
+		for _, word := range tokenize(text) {
+			for t, topic := range fakeTopics {
+				if topic[word] {
+					v[t]++
+				}
+			}
+		}
+		vectors[i] = v
+	}
+	return vectors, nil
+}
+
+func TestSemanticSearch(t *testing.T) {
+	store, _ := searchStore(t)
+	embedder := &fakeEmbedder{model: "fake"}
+	if err := store.EnableSemantic(embedder); err != nil {
+		t.Fatal(err)
+	}
+
+	apc, err := store.Add(Note{Text: "APC replacement ordered for rack 2"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := store.Search(Query{Terms: []string{"ups", "battery"}, Semantic: true, PageSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(result.Notes); !reflect.DeepEqual(got, []string{apc.ID}) {
+		t.Errorf("Expected semantic search to find [%s], got %v", apc.ID, got)
+	}
+
+	// Keyword search can't find it: no word in common.
+	keyword, err := store.Search(Query{Terms: []string{"ups", "battery"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyword.Total != 0 {
+		t.Errorf("Expected keyword search to find nothing, got %v", ids(keyword.Notes))
+	}
+
+	// Filters still apply.
+	result, err = store.Search(Query{Terms: []string{"storage"}, Tags: []string{"nas"}, Semantic: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(result.Notes); !reflect.DeepEqual(got, []string{"n3"}) {
+		t.Errorf("Expected filtered semantic search to find [n3], got %v", got)
+	}
+}
+
+func TestSyncEmbeddingsIncremental(t *testing.T) {
+	store, dir := searchStore(t)
+	embedder := &fakeEmbedder{model: "fake"}
+	if err := store.EnableSemantic(embedder); err != nil {
+		t.Fatal(err)
+	}
+
+	embedded, err := store.SyncEmbeddings(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if embedded != 5 {
+		t.Fatalf("Expected the first sync to embed 5 notes, got %d", embedded)
+	}
+
+	if embedded, _ := store.SyncEmbeddings(context.Background()); embedded != 0 {
+		t.Errorf("Expected unchanged notes not to be embedded again, got %d", embedded)
+	}
+
+	if _, err := store.Edit("n2", "lunch with the ops team", "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Remove("n5", "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if embedded, _ := store.SyncEmbeddings(context.Background()); embedded != 1 {
+		t.Errorf("Expected 1 note embedded after one edit, got %d", embedded)
+	}
+	if _, ok := store.sem.vectors["n5"]; ok {
+		t.Error("Expected the removed note's vector to be dropped")
+	}
+
+	// A restart loads the saved vectors instead of embedding everything again.
+	reopened, err := NewStore(dir, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again := &fakeEmbedder{model: "fake"}
+	if err := reopened.EnableSemantic(again); err != nil {
+		t.Fatal(err)
+	}
+	if embedded, _ := reopened.SyncEmbeddings(context.Background()); embedded != 0 {
+		t.Errorf("Expected the reopened store to reuse saved vectors, embedded %d", embedded)
+	}
+
+	// A different model can't reuse the vectors.
+	other, err := NewStore(dir, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.EnableSemantic(&fakeEmbedder{model: "other"}); err != nil {
+		t.Fatal(err)
+	}
+	if embedded, _ := other.SyncEmbeddings(context.Background()); embedded != 4 {
+		t.Errorf("Expected a new model to embed 4 notes, got %d", embedded)
+	}
+}
+
+func TestSemanticSearchNeedsWords(t *testing.T) {
+	store, _ := searchStore(t)
+	if err := store.EnableSemantic(&fakeEmbedder{model: "fake"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Search(Query{Tags: []string{"ops"}, Semantic: true}); err == nil {
+		t.Error("Expected an error for a semantic search without words")
+	}
+}
@@ -0,0 +1,278 @@
+package reminders
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/google/uuid"
+)
+
+const (
+	// ButtonPrefix starts the custom ID of the buttons on a fired reminder:
+	// "remind:<id>:<15m|1h|1d|done>".
+	ButtonPrefix = "remind"
+
+	checkInterval = 15 * time.Second
+	// firedRetention is how long a fired reminder can still be snoozed.
+	firedRetention = 24 * time.Hour
+)
+
+// SnoozeOptions are the snooze buttons offered on a fired reminder.
+var SnoozeOptions = []struct {
+	Label string
+	Arg   string
+	After time.Duration
+}{
+	{"15 min", "15m", 15 * time.Minute},
+	{"1 hour", "1h", time.Hour},
+	{"Tomorrow", "1d", 24 * time.Hour},
+}
+
+type Reminder struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	ChannelID string    `json:"channel_id"`
+	DM        bool      `json:"dm,omitempty"`
+	Text      string    `json:"text"`
+	At        time.Time `json:"at"`
+	Created   time.Time `json:"created"`
+	Fired     bool      `json:"fired,omitempty"`
+}
+
+// Manager keeps one-time reminders in DATA_DIR/reminders.json and delivers
+// them when due, next to the cron-based scheduler.
+type Manager struct {
+	session   *discordgo.Session
+	path      string
+	loc       *time.Location
+	mu        sync.Mutex
+	reminders map[string]*Reminder
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func New(session *discordgo.Session, dataDir string, loc *time.Location) (*Manager, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("create directory %s: %w", dataDir, err)
+	}
+
+	m := &Manager{
+		session:   session,
+		path:      filepath.Join(dataDir, "reminders.json"),
+		loc:       loc,
+		reminders: map[string]*Reminder{},
+	}
+
+	data, err := os.ReadFile(m.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(data) > 0 {
+		var list []*Reminder
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", m.path, err)
+		}
+		for _, r := range list {
+			m.reminders[r.ID] = r
+		}
+	}
+	return m, nil
+}
+
+// Now is the current time in loc, or in the default timezone when loc is nil.
+func (m *Manager) Now(loc *time.Location) time.Time {
+	if loc == nil {
+		loc = m.loc
+	}
+	return time.Now().In(loc)
+}
+
+// Add schedules a reminder and returns it with its ID filled in.
+func (m *Manager) Add(r Reminder) (Reminder, error) {
+	r.Text = strings.TrimSpace(r.Text)
+	if r.Text == "" {
+		return Reminder{}, fmt.Errorf("reminder text is required")
+	}
+
+	r.ID = uuid.NewString()[:8]
+	r.Created = time.Now()
+	r.Fired = false
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.reminders[r.ID] = &r
+	return r, m.save()
+}
+
+// Snooze reschedules a reminder owned by userID to fire again after d.
+func (m *Manager) Snooze(id, userID string, d time.Duration) (Reminder, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.owned(id, userID)
+	if err != nil {
+		return Reminder{}, err
+	}
+
+	r.At = time.Now().Add(d)
+	r.Fired = false
+	return *r, m.save()
+}
+
+// Cancel deletes a reminder owned by userID.
+func (m *Manager) Cancel(id, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.owned(id, userID); err != nil {
+		return err
+	}
+	delete(m.reminders, id)
+	return m.save()
+}
+
+func (m *Manager) owned(id, userID string) (*Reminder, error) {
+	r, ok := m.reminders[id]
+	if !ok {
+		return nil, fmt.Errorf("reminder not found")
+	}
+	if r.UserID != userID {
+		return nil, fmt.Errorf("only the person who set this reminder can change it")
+	}
+	return r, nil
+}
+
+// Start delivers due reminders until Stop. Reminders that came due while the
+// bot was down fire on the first check.
+func (m *Manager) Start() {
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	m.mu.Lock()
+	log.Printf("Reminders started with %d pending", len(m.reminders))
+	m.mu.Unlock()
+
+	go func() {
+		defer close(m.done)
+
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		for {
+			m.fireDue()
+			select {
+			case <-ticker.C:
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (m *Manager) Stop() {
+	if m.stop == nil {
+		return
+	}
+	close(m.stop)
+	<-m.done
+	log.Println("Reminders stopped")
+}
+
+func (m *Manager) fireDue() {
+	now := time.Now()
+
+	m.mu.Lock()
+	var due []Reminder
+	changed := false
+	for id, r := range m.reminders {
+		switch {
+		case r.Fired && now.Sub(r.At) > firedRetention:
+			delete(m.reminders, id)
+			changed = true
+		case !r.Fired && !r.At.After(now):
+			r.Fired = true
+			due = append(due, *r)
+			changed = true
+		}
+	}
+	if changed {
+		if err := m.save(); err != nil {
+			log.Printf("Failed to save reminders: %v", err)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, r := range due {
+		m.deliver(r)
+	}
+}
+
+// deliver mentions the user where the reminder was set, or DMs them when
+// asked to or when the channel is gone.
+func (m *Manager) deliver(r Reminder) {
+	msg := &discordgo.MessageSend{
+		Content:    fmt.Sprintf("⏰ <@%s> Reminder: %s", r.UserID, r.Text),
+		Components: buttons(r.ID),
+	}
+	if late := time.Since(r.At); late > time.Minute {
+		msg.Content += fmt.Sprintf("\n-# Due <t:%d:R>, delivered late.", r.At.Unix())
+	}
+
+	if !r.DM && r.ChannelID != "" {
+		_, err := m.session.ChannelMessageSendComplex(r.ChannelID, msg)
+		if err == nil {
+			return
+		}
+		log.Printf("Failed to send reminder %s to channel %s, trying DM: %v", r.ID, r.ChannelID, err)
+	}
+
+	dm, err := m.session.UserChannelCreate(r.UserID)
+	if err != nil {
+		log.Printf("Failed to open DM for reminder %s: %v", r.ID, err)
+		return
+	}
+	if _, err := m.session.ChannelMessageSendComplex(dm.ID, msg); err != nil {
+		log.Printf("Failed to deliver reminder %s: %v", r.ID, err)
+	}
+}
+
+func buttons(id string) []discordgo.MessageComponent {
+	var row []discordgo.MessageComponent
+	for _, opt := range SnoozeOptions {
+		row = append(row, discordgo.Button{
+			Label:    "Snooze " + opt.Label,
+			Style:    discordgo.SecondaryButton,
+			CustomID: ButtonPrefix + ":" + id + ":" + opt.Arg,
+		})
+	}
+	row = append(row, discordgo.Button{
+		Label:    "Done",
+		Style:    discordgo.SuccessButton,
+		CustomID: ButtonPrefix + ":" + id + ":done",
+	})
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: row}}
+}
+
+// save writes all reminders; the caller holds mu.
+func (m *Manager) save() error {
+	list := make([]*Reminder, 0, len(m.reminders))
+	for _, r := range m.reminders {
+		list = append(list, r)
+	}
+	slices.SortFunc(list, func(a, b *Reminder) int { return a.At.Compare(b.At) })
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(m.path, data, 0644)
+}
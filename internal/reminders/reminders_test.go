@@ -0,0 +1,56 @@
+package reminders
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManagerSnoozeAndPersist(t *testing.T) {
+	dir := t.TempDir()
+	m, err := New(nil, dir, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := m.Add(Reminder{UserID: "u1", ChannelID: "c1", Text: "check the backup", At: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Snooze(r.ID, "someone-else", time.Hour); err == nil {
+		t.Error("Expected snoozing someone else's reminder to fail")
+	}
+	if _, err := m.Snooze(r.ID, "u1", 2*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := New(nil, dir, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left := pending(reloaded, "u1")
+	if len(left) != 1 || left[0].Text != "check the backup" || time.Until(left[0].At) < 90*time.Minute {
+		t.Errorf("Expected the snoozed reminder after reload, got %+v", left)
+	}
+
+	if err := reloaded.Cancel(r.ID, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	if left := pending(reloaded, "u1"); len(left) != 0 {
+		t.Errorf("Expected no reminders after cancel, got %+v", left)
+	}
+}
+
+// pending returns userID's reminders that haven't fired.
+func pending(m *Manager, userID string) []Reminder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var list []Reminder
+	for _, r := range m.reminders {
+		if r.UserID == userID && !r.Fired {
+			list = append(list, *r)
+		}
+	}
+	return list
+}
@@ -0,0 +1,80 @@
+package router
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/marshall/zero-ops-bot/internal/metadata"
+)
+
+// fallbackKeywords drive the local classifier used when the LLM router is
+// unavailable. Only workflows present in the catalog are considered.
+var fallbackKeywords = map[string][]string{
+	"infra":  {"deploy", "restart", "rollout", "rollback", "docker", "kubectl", "k8s", "pod", "pods", "container", "containers", "logs", "helm"},
+	"health": {"uptime", "disk", "memory", "cpu", "load", "ping", "health", "status", "latency", "temperature"},
+	"note":   {"remember", "note that", "don't forget", "save this", "my notes", "jot down"},
+}
+
+// actingWorkflows change things rather than just read them. A keyword alone
+// is too weak a signal to run one, so the classifier only picks them when
+// the catalog puts them behind approval.
+var actingWorkflows = map[string]bool{
+	"infra": true,
+}
+
+// keywordPatterns match each keyword as whole words, so "pod" doesn't hit
+// "podcast" or "ping" "shopping".
+var keywordPatterns = compileKeywords(fallbackKeywords)
+
+func compileKeywords(keywords map[string][]string) map[string][]*regexp.Regexp {
+	patterns := make(map[string][]*regexp.Regexp, len(keywords))
+	for name, list := range keywords {
+		for _, kw := range list {
+			patterns[name] = append(patterns[name], regexp.MustCompile(`\b`+regexp.QuoteMeta(kw)+`\b`))
+		}
+	}
+	return patterns
+}
+
+var failures atomic.Int64
+
+// RecordFailure counts a failed call to the LLM router and returns the total
+// since startup.
+func RecordFailure() int64 {
+	return failures.Add(1)
+}
+
+// Classify picks a workflow by whole-word keyword hits. Workflows that take
+// actions are skipped unless they require approval. It returns false when
+// nothing matches or two workflows tie, leaving the caller to fall back
+// further.
+func Classify(catalog []metadata.Workflow, content string) (string, bool) {
+	lower := strings.ToLower(content)
+
+	best, bestScore, tied := "", 0, false
+	for _, wf := range catalog {
+		if actingWorkflows[wf.Name] && !wf.RequireApproval {
+			continue
+		}
+
+		score := 0
+		for _, kw := range keywordPatterns[wf.Name] {
+			if kw.MatchString(lower) {
+				score++
+			}
+		}
+
+		switch {
+		case score > bestScore:
+			best, bestScore, tied = wf.Name, score, false
+		case score == bestScore && score > 0:
+			tied = true
+		}
+	}
+
+	if bestScore == 0 || tied {
+		return "", false
+	}
+	return best, true
+}
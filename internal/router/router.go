@@ -0,0 +1,136 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/marshall/zero-ops-bot/internal/metadata"
+)
+
+// Match is the outcome of a pre-router rule that fired.
+type Match struct {
+	Rule    string
+	Command string
+	Content string
+}
+
+// templateData is what a route's content template is rendered with.
+type templateData struct {
+	Message string
+	Groups  map[string]string
+	Matches []string
+}
+
+var (
+	patterns  sync.Map // pattern string -> *regexp.Regexp
+	templates sync.Map // template string -> *template.Template
+)
+
+var funcs = template.FuncMap{
+	"json": func(v string) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// Evaluate runs routes in order against content and returns the first hit.
+// Rules with an invalid pattern or template are logged and skipped so a bad
+// entry in metadata never blocks the LLM fallback.
+func Evaluate(routes []metadata.Route, content string) (*Match, bool) {
+	for _, route := range routes {
+		data, ok := matchRoute(route, content)
+		if !ok {
+			continue
+		}
+
+		rendered, err := render(route.Content, data)
+		if err != nil {
+			log.Printf("Route %q template failed: %v", route.Name, err)
+			continue
+		}
+
+		return &Match{
+			Rule:    route.Name,
+			Command: route.Command,
+			Content: rendered,
+		}, true
+	}
+	return nil, false
+}
+
+func matchRoute(route metadata.Route, content string) (*templateData, bool) {
+	data := &templateData{
+		Message: content,
+		Groups:  map[string]string{},
+	}
+
+	if route.Pattern != "" {
+		re, err := compile(route.Pattern)
+		if err != nil {
+			log.Printf("Route %q has invalid pattern: %v", route.Name, err)
+			return nil, false
+		}
+
+		m := re.FindStringSubmatch(content)
+		if m != nil {
+			data.Matches = m
+			for i, name := range re.SubexpNames() {
+				if name != "" {
+					data.Groups[name] = m[i]
+				}
+			}
+			return data, true
+		}
+	}
+
+	lower := strings.ToLower(content)
+	for _, kw := range route.Keywords {
+		if kw != "" && strings.Contains(lower, strings.ToLower(kw)) {
+			return data, true
+		}
+	}
+
+	return nil, false
+}
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+func render(text string, data *templateData) (string, error) {
+	if text == "" {
+		return data.Message, nil
+	}
+
+	var tmpl *template.Template
+	if cached, ok := templates.Load(text); ok {
+		tmpl = cached.(*template.Template)
+	} else {
+		parsed, err := template.New("route").Funcs(funcs).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return "", fmt.Errorf("parse template: %w", err)
+		}
+		templates.Store(text, parsed)
+		tmpl = parsed
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,92 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/marshall/zero-ops-bot/internal/metadata"
+)
+
+var testRoutes = []metadata.Route{
+	{
+		Name:    "remember",
+		Pattern: `(?i)^(?:remember|note that)\s+(?P<text>.+)$`,
+		Command: "note",
+		Content: `{"action":"add","text":{{json .Groups.text}},"category":"daily"}`,
+	},
+	{
+		Name:     "disk",
+		Keywords: []string{"disk usage", "status of disk"},
+		Command:  "health",
+	},
+	{
+		Name:    "broken",
+		Pattern: `(`,
+		Command: "chat",
+	},
+}
+
+func TestEvaluate_PatternWithTemplate(t *testing.T) {
+	hit, ok := Evaluate(testRoutes, `Remember the NAS "admin" password rotates monthly`)
+	if !ok {
+		t.Fatal("Expected remember route to match")
+	}
+
+	want := `{"action":"add","text":"the NAS \"admin\" password rotates monthly","category":"daily"}`
+	if hit.Rule != "remember" || hit.Command != "note" || hit.Content != want {
+		t.Errorf("Unexpected match: %+v", hit)
+	}
+}
+
+func TestEvaluate_KeywordForwardsMessage(t *testing.T) {
+	msg := "what's the Status of Disk on nas?"
+	hit, ok := Evaluate(testRoutes, msg)
+	if !ok {
+		t.Fatal("Expected disk route to match")
+	}
+
+	if hit.Command != "health" || hit.Content != msg {
+		t.Errorf("Unexpected match: %+v", hit)
+	}
+}
+
+func TestEvaluate_NoMatchFallsThrough(t *testing.T) {
+	if hit, ok := Evaluate(testRoutes, "deploy the blog"); ok {
+		t.Errorf("Expected no match, got %+v", hit)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	catalog := []metadata.Workflow{{Name: "infra", RequireApproval: true}, {Name: "health"}, {Name: "chat"}}
+
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"restart the docker container for plex", "infra", true},
+		{"how is disk and memory looking?", "health", true},
+		{"remember to buy milk", "", false}, // note is not in the catalog
+		{"tell me a joke", "", false},
+		{"restart and check disk", "", false}, // tie
+		{"any good podcasts about shopping?", "", false},
+		{"download the blogs for me", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Classify(catalog, tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Classify(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestClassify_SkipsUnapprovedActions(t *testing.T) {
+	catalog := []metadata.Workflow{{Name: "infra"}, {Name: "health"}}
+
+	if got, ok := Classify(catalog, "restart the docker container for plex"); ok {
+		t.Errorf("Expected no keyword route to infra without approval, got %q", got)
+	}
+	if got, ok := Classify(catalog, "check the disk"); !ok || got != "health" {
+		t.Errorf("Expected health, got %q, %v", got, ok)
+	}
+}
@@ -22,13 +22,7 @@ type Scheduler struct {
 	notes   *notes.Store
 }
 
-func New(session *discordgo.Session, n8n *services.N8nClient, notesStore *notes.Store, timezone string) *Scheduler {
-	loc, err := time.LoadLocation(timezone)
-	if err != nil {
-		log.Printf("Invalid timezone %q, using Local: %v", timezone, err)
-		loc = time.Local
-	}
-
+func New(session *discordgo.Session, n8n *services.N8nClient, notesStore *notes.Store, loc *time.Location) *Scheduler {
 	return &Scheduler{
 		cron:    cron.New(cron.WithLocation(loc)),
 		session: session,
@@ -108,10 +102,45 @@ func (s *Scheduler) run(schedule metadata.Schedule) {
 	}
 
 	if schedule.IncludeNotes && s.notes != nil {
-		today := time.Now().Format("2006-01-02")
+		today := s.notes.Today(nil)
 		content += fmt.Sprintf("\n\n## Notes\nNotes directory: %s\nToday's notes: daily/%s.md", s.notes.BaseDir(), today)
 	}
 
+	var todos []services.TodoMeta
+	if schedule.IncludeTodos && s.notes != nil {
+		open, err := s.notes.OpenTodos()
+		if err != nil {
+			log.Printf("Schedule %s failed to read todos: %v", schedule.Name, err)
+		}
+
+		today := s.notes.Today(nil)
+		var todoLines []string
+		for _, n := range open {
+			todos = append(todos, services.TodoMeta{
+				ID:       n.ID,
+				Text:     n.Text,
+				Due:      n.Due,
+				Priority: n.Priority,
+				Source:   n.Source(),
+			})
+
+			line := "- " + n.Text
+			if n.Due != "" {
+				line += " (due " + n.Due + ")"
+				if n.Overdue(today) {
+					line += " OVERDUE"
+				}
+			}
+			if n.Priority != "" {
+				line += " [" + n.Priority + "]"
+			}
+			todoLines = append(todoLines, line)
+		}
+		if len(todoLines) > 0 {
+			content += "\n\n## Open Todos\n" + strings.Join(todoLines, "\n")
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
@@ -121,6 +150,7 @@ func (s *Scheduler) run(schedule metadata.Schedule) {
 		ChannelID: schedule.ChannelID,
 		Content:   content,
 		Repos:     repos,
+		Todos:     todos,
 	})
 	if err != nil {
 		log.Printf("Schedule %s webhook failed: %v", schedule.Name, err)
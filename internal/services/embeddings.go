@@ -0,0 +1,102 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// EmbeddingsClient talks to an OpenAI-compatible embeddings endpoint, such
+// as a local model server.
+type EmbeddingsClient struct {
+	url        string
+	model      string
+	apiKey     string
+	httpClient *http.Client
+}
+
+type embeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// NewEmbeddingsClient takes the API base URL (e.g. http://localhost:8080/v1);
+// requests go to its /embeddings path.
+func NewEmbeddingsClient(baseURL, model, apiKey string) *EmbeddingsClient {
+	return &EmbeddingsClient{
+		url:    strings.TrimSuffix(baseURL, "/") + "/embeddings",
+		model:  model,
+		apiKey: apiKey,
+		httpClient: &http.Client{
+			Timeout: time.Minute,
+		},
+	}
+}
+
+func (c *EmbeddingsClient) Model() string {
+	return c.model
+}
+
+// Embed returns one vector per text, in the order given.
+func (c *EmbeddingsClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(embeddingsRequest{Model: c.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	var result embeddingsResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range result.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("response has embedding for unknown input %d", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("response is missing the embedding for input %d", i)
+		}
+	}
+	return vectors, nil
+}
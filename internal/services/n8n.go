@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -25,19 +26,33 @@ type RepoMeta struct {
 	Path        string `json:"path"`
 }
 
+type TodoMeta struct {
+	ID       string `json:"id"`
+	Text     string `json:"text"`
+	Due      string `json:"due,omitempty"`
+	Priority string `json:"priority,omitempty"`
+	Source   string `json:"source"`
+}
+
 type WebhookPayload struct {
-	Type         string     `json:"type"`
-	Command      string     `json:"command,omitempty"`
-	Content      string     `json:"content,omitempty"`
-	UserID       string     `json:"user_id"`
-	UserName     string     `json:"user_name,omitempty"`
-	ChannelID    string     `json:"channel_id"`
-	MessageID    string     `json:"message_id,omitempty"`
-	ThreadID     string     `json:"thread_id,omitempty"`
-	SessionID    string     `json:"session_id,omitempty"`
-	Timestamp    string     `json:"timestamp"`
-	Source string     `json:"source"`
-	Repos []RepoMeta `json:"repos,omitempty"`
+	Type      string     `json:"type"`
+	Command   string     `json:"command,omitempty"`
+	Content   string     `json:"content,omitempty"`
+	Message   string     `json:"message,omitempty"`
+	Rating    string     `json:"rating,omitempty"`
+	UserID    string     `json:"user_id"`
+	UserName  string     `json:"user_name,omitempty"`
+	ChannelID string     `json:"channel_id"`
+	MessageID string     `json:"message_id,omitempty"`
+	ThreadID  string     `json:"thread_id,omitempty"`
+	SessionID string     `json:"session_id,omitempty"`
+	Timestamp string     `json:"timestamp"`
+	Source    string     `json:"source"`
+	Repos     []RepoMeta `json:"repos,omitempty"`
+	Todos     []TodoMeta `json:"todos,omitempty"`
+	// IdempotencyKey is also sent as the Idempotency-Key header so workflows
+	// can drop duplicate deliveries.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
 
 type WebhookResponse struct {
@@ -46,11 +61,21 @@ type WebhookResponse struct {
 	Data    any    `json:"data,omitempty"`
 }
 
+// AnalyzeResponse is the router's decision. Confidence (0-1) and Candidates
+// are optional; when omitted the decision is taken as certain. Title is an
+// optional short thread title.
 type AnalyzeResponse struct {
-	Command string `json:"command"`
-	Content string `json:"content"`
+	Command    string   `json:"command"`
+	Content    string   `json:"content"`
+	Title      string   `json:"title,omitempty"`
+	Confidence *float64 `json:"confidence,omitempty"`
+	Candidates []string `json:"candidates,omitempty"`
 }
 
+// ErrInvalidAnalyzeResponse is returned when the router replies with
+// something that isn't a JSON routing decision.
+var ErrInvalidAnalyzeResponse = errors.New("router returned an invalid response")
+
 func NewN8nClient(webhookURL, webhookSecret string) *N8nClient {
 	return &N8nClient{
 		webhookURL:    webhookURL,
@@ -62,46 +87,45 @@ func NewN8nClient(webhookURL, webhookSecret string) *N8nClient {
 }
 
 func (c *N8nClient) TriggerWebhook(ctx context.Context, payload WebhookPayload) (*WebhookResponse, error) {
-	payload.Timestamp = time.Now().UTC().Format(time.RFC3339)
-	payload.Source = "zero-ops-bot"
-
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("marshal payload: %w", err)
-	}
+	return c.TriggerWebhookAt(ctx, "", payload)
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookURL, bytes.NewReader(body))
+// TriggerWebhookAt is TriggerWebhook against a specific webhook URL, used by
+// workflows that override the global endpoint. An empty url means the default.
+func (c *N8nClient) TriggerWebhookAt(ctx context.Context, url string, payload WebhookPayload) (*WebhookResponse, error) {
+	respBody, err := c.post(ctx, url, payload)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	if c.webhookSecret != "" {
-		req.Header.Set("x-discord-api-key", c.webhookSecret)
-	}
+	return &WebhookResponse{
+		Success: true,
+		Message: string(respBody),
+	}, nil
+}
 
-	resp, err := c.httpClient.Do(req)
+func (c *N8nClient) TriggerWebhookJSON(ctx context.Context, payload WebhookPayload) (*AnalyzeResponse, error) {
+	respBody, err := c.post(ctx, "", payload)
 	if err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
-	}
+	cleaned := extractJSON(string(respBody))
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
+	var result AnalyzeResponse
+	if err := json.Unmarshal([]byte(cleaned), &result); err != nil || result.Command == "" {
+		// n8n returned plain text instead of JSON — never guess an execution from it
+		return nil, ErrInvalidAnalyzeResponse
 	}
 
-	return &WebhookResponse{
-		Success: true,
-		Message: string(respBody),
-	}, nil
+	return &result, nil
 }
 
-func (c *N8nClient) TriggerWebhookJSON(ctx context.Context, payload WebhookPayload) (*AnalyzeResponse, error) {
+func (c *N8nClient) post(ctx context.Context, url string, payload WebhookPayload) ([]byte, error) {
+	if url == "" {
+		url = c.webhookURL
+	}
+
 	payload.Timestamp = time.Now().UTC().Format(time.RFC3339)
 	payload.Source = "zero-ops-bot"
 
@@ -110,7 +134,7 @@ func (c *N8nClient) TriggerWebhookJSON(ctx context.Context, payload WebhookPaylo
 		return nil, fmt.Errorf("marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookURL, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -119,6 +143,9 @@ func (c *N8nClient) TriggerWebhookJSON(ctx context.Context, payload WebhookPaylo
 	if c.webhookSecret != "" {
 		req.Header.Set("x-discord-api-key", c.webhookSecret)
 	}
+	if payload.IdempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", payload.IdempotencyKey)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -134,19 +161,7 @@ func (c *N8nClient) TriggerWebhookJSON(ctx context.Context, payload WebhookPaylo
 	if err != nil {
 		return nil, fmt.Errorf("read response: %w", err)
 	}
-
-	cleaned := extractJSON(string(respBody))
-
-	var result AnalyzeResponse
-	if err := json.Unmarshal([]byte(cleaned), &result); err != nil {
-		// n8n returned plain text instead of JSON — reject to avoid broken execution
-		return &AnalyzeResponse{
-			Command: "reject",
-			Content: "Sorry, I couldn't process that request. Please try again.",
-		}, nil
-	}
-
-	return &result, nil
+	return respBody, nil
 }
 
 func extractJSON(s string) string {
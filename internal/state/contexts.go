@@ -0,0 +1,44 @@
+package state
+
+import (
+	"github.com/google/uuid"
+)
+
+// ThreadContext is per-thread state that must survive restarts.
+type ThreadContext struct {
+	// Salt is mixed into the session ID; rotating it starts a fresh session.
+	Salt string `json:"salt,omitempty"`
+	// Repo limits the repositories sent with the thread's requests.
+	Repo string `json:"repo,omitempty"`
+}
+
+var contexts = newJSONStore[ThreadContext]("threads.json")
+
+// LoadContexts reads thread contexts from dataDir and persists later changes
+// there. Without it contexts are kept in memory only.
+func LoadContexts(dataDir string) error {
+	return contexts.load(dataDir)
+}
+
+// ResetSession rotates the thread's salt so it gets a new session ID.
+func ResetSession(threadID string) error {
+	return contexts.update(threadID, func(c *ThreadContext) {
+		c.Salt = uuid.NewString()[:8]
+	})
+}
+
+// BindRepo focuses a thread on one repository; an empty name clears it.
+func BindRepo(threadID, repo string) error {
+	return contexts.update(threadID, func(c *ThreadContext) {
+		c.Repo = repo
+	})
+}
+
+// ThreadRepo returns the repository a thread is bound to, if any.
+func ThreadRepo(threadID string) string {
+	return contexts.get(threadID).Repo
+}
+
+func threadSalt(threadID string) string {
+	return contexts.get(threadID).Salt
+}
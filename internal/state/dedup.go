@@ -0,0 +1,40 @@
+package state
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	seen   = map[string]time.Time{}
+	seenMu sync.Mutex
+)
+
+// FirstSeen records key and reports whether it was not already recorded
+// within ttl. Gateway replays and double clicks share a key and so only the
+// first delivery does any work.
+func FirstSeen(key string, ttl time.Duration) bool {
+	seenMu.Lock()
+	defer seenMu.Unlock()
+
+	now := time.Now()
+	for k, expires := range seen {
+		if now.After(expires) {
+			delete(seen, k)
+		}
+	}
+
+	if _, ok := seen[key]; ok {
+		return false
+	}
+	seen[key] = now.Add(ttl)
+	return true
+}
+
+// Forget drops a key recorded by FirstSeen, for work that failed and may be
+// tried again.
+func Forget(key string) {
+	seenMu.Lock()
+	defer seenMu.Unlock()
+	delete(seen, key)
+}
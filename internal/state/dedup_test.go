@@ -0,0 +1,33 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFirstSeen_RejectsDuplicates(t *testing.T) {
+	if !FirstSeen("message:1", time.Minute) {
+		t.Error("Expected first delivery to be new")
+	}
+	if FirstSeen("message:1", time.Minute) {
+		t.Error("Expected duplicate delivery to be rejected")
+	}
+}
+
+func TestFirstSeen_ExpiresAfterTTL(t *testing.T) {
+	if !FirstSeen("message:2", time.Nanosecond) {
+		t.Error("Expected first delivery to be new")
+	}
+	time.Sleep(time.Millisecond)
+	if !FirstSeen("message:2", time.Nanosecond) {
+		t.Error("Expected key to be accepted again after its TTL")
+	}
+}
+
+func TestForget(t *testing.T) {
+	FirstSeen("message:3", time.Minute)
+	Forget("message:3")
+	if !FirstSeen("message:3", time.Minute) {
+		t.Error("Expected a forgotten key to be accepted again")
+	}
+}
@@ -0,0 +1,69 @@
+package state
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PendingTTL is how long an action waits for a button click before it is dropped.
+const PendingTTL = 30 * time.Minute
+
+type pendingEntry struct {
+	value   any
+	expires time.Time
+}
+
+var (
+	pending   = map[string]pendingEntry{}
+	pendingMu sync.Mutex
+)
+
+// PutPending stores an action awaiting user input and returns a short ID to
+// embed in component custom IDs.
+func PutPending(value any) string {
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+
+	now := time.Now()
+	for id, e := range pending {
+		if now.After(e.expires) {
+			delete(pending, id)
+		}
+	}
+
+	id := uuid.NewString()[:8]
+	pending[id] = pendingEntry{value: value, expires: now.Add(PendingTTL)}
+	return id
+}
+
+// TakePending removes and returns a pending action. It reports false when the
+// ID is unknown or expired, so a second click on the same button is a no-op.
+func TakePending(id string) (any, bool) {
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+
+	e, ok := pending[id]
+	if !ok {
+		return nil, false
+	}
+	delete(pending, id)
+
+	if time.Now().After(e.expires) {
+		return nil, false
+	}
+	return e.value, true
+}
+
+// PeekPending returns a pending action without consuming it.
+func PeekPending(id string) (any, bool) {
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+
+	e, ok := pending[id]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+	return e.value, true
+}
@@ -0,0 +1,54 @@
+package state
+
+import (
+	"sync"
+	"time"
+)
+
+// replyRetention bounds how long source → reply links are remembered.
+const replyRetention = 24 * time.Hour
+
+// ReplyLink records the bot messages sent in answer to a user message.
+type ReplyLink struct {
+	ChannelID  string
+	MessageIDs []string
+	Created    time.Time
+}
+
+var (
+	replies   = map[string]*ReplyLink{}
+	repliesMu sync.Mutex
+)
+
+// LinkReply records that replyID, sent in channelID, answers sourceID.
+func LinkReply(sourceID, channelID, replyID string) {
+	repliesMu.Lock()
+	defer repliesMu.Unlock()
+
+	now := time.Now()
+	for id, link := range replies {
+		if now.Sub(link.Created) > replyRetention {
+			delete(replies, id)
+		}
+	}
+
+	link, ok := replies[sourceID]
+	if !ok {
+		link = &ReplyLink{ChannelID: channelID, Created: now}
+		replies[sourceID] = link
+	}
+	link.MessageIDs = append(link.MessageIDs, replyID)
+}
+
+// TakeReplies removes and returns the replies linked to sourceID.
+func TakeReplies(sourceID string) (ReplyLink, bool) {
+	repliesMu.Lock()
+	defer repliesMu.Unlock()
+
+	link, ok := replies[sourceID]
+	if !ok {
+		return ReplyLink{}, false
+	}
+	delete(replies, sourceID)
+	return *link, true
+}
@@ -8,7 +8,19 @@ import (
 var sessionNamespace = uuid.MustParse("a1b2c3d4-e5f6-7890-abcd-ef1234567890")
 
 // ThreadIDToSessionID generates a deterministic UUID from a Discord thread ID.
-// Same thread ID always produces the same session UUID.
+// Same thread ID always produces the same session UUID until the thread's
+// session is reset with ResetSession.
 func ThreadIDToSessionID(threadID string) string {
-	return uuid.NewSHA1(sessionNamespace, []byte(threadID)).String()
+	key := threadID
+	if salt := threadSalt(threadID); salt != "" {
+		key += ":" + salt
+	}
+	return uuid.NewSHA1(sessionNamespace, []byte(key)).String()
+}
+
+// AskSessionKey is the session key of a user's /ask conversation in a
+// channel. /ask keeps one session per user so people asking in the same
+// channel don't share memory.
+func AskSessionKey(channelID, userID string) string {
+	return channelID + ":" + userID
 }
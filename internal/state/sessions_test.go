@@ -29,3 +29,56 @@ func TestThreadIDToSessionID_DifferentThreads(t *testing.T) {
 	t.Logf("Thread %s -> %s", thread1, id1)
 	t.Logf("Thread %s -> %s", thread2, id2)
 }
+
+func TestResetSession(t *testing.T) {
+	if err := LoadContexts(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	threadID := "1111111111111111111"
+
+	before := ThreadIDToSessionID(threadID)
+	if err := ResetSession(threadID); err != nil {
+		t.Fatal(err)
+	}
+	after := ThreadIDToSessionID(threadID)
+
+	if before == after {
+		t.Errorf("Expected a new session ID after reset, got %s again", after)
+	}
+	if again := ThreadIDToSessionID(threadID); again != after {
+		t.Errorf("Expected reset session ID to be stable, got %s and %s", after, again)
+	}
+}
+
+func TestContextsPersist(t *testing.T) {
+	dir := t.TempDir()
+	if err := LoadContexts(dir); err != nil {
+		t.Fatal(err)
+	}
+	threadID := "2222222222222222222"
+
+	if err := BindRepo(threadID, "zero-ops-bot"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ResetSession(threadID); err != nil {
+		t.Fatal(err)
+	}
+	session := ThreadIDToSessionID(threadID)
+
+	if err := LoadContexts(dir); err != nil {
+		t.Fatal(err)
+	}
+	if repo := ThreadRepo(threadID); repo != "zero-ops-bot" {
+		t.Errorf("Expected bound repo to survive reload, got %q", repo)
+	}
+	if got := ThreadIDToSessionID(threadID); got != session {
+		t.Errorf("Expected session %s after reload, got %s", session, got)
+	}
+
+	if err := BindRepo(threadID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if repo := ThreadRepo(threadID); repo != "" {
+		t.Errorf("Expected binding to be cleared, got %q", repo)
+	}
+}
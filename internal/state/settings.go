@@ -0,0 +1,47 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// UserSettings are a user's personal preferences.
+type UserSettings struct {
+	// Timezone decides which daily file "today" is for the user; empty
+	// means the bot's TZ.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+var settings = newJSONStore[UserSettings]("settings.json")
+
+// LoadSettings reads user settings from dataDir and persists later changes
+// there. Without it settings are kept in memory only.
+func LoadSettings(dataDir string) error {
+	return settings.load(dataDir)
+}
+
+// SetTimezone sets a user's IANA timezone, such as "Europe/Berlin"; an empty
+// name clears it.
+func SetTimezone(userID, name string) error {
+	if name != "" {
+		if _, err := time.LoadLocation(name); err != nil {
+			return fmt.Errorf("unknown timezone %q, use a name like Europe/Berlin", name)
+		}
+	}
+	return settings.update(userID, func(u *UserSettings) {
+		u.Timezone = name
+	})
+}
+
+// UserLocation returns the user's timezone, or nil when they haven't set one.
+func UserLocation(userID string) *time.Location {
+	name := settings.get(userID).Timezone
+	if name == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil
+	}
+	return loc
+}
@@ -0,0 +1,48 @@
+package state
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSetTimezone(t *testing.T) {
+	dir := t.TempDir()
+	if err := LoadSettings(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		settings = newJSONStore[UserSettings]("settings.json")
+	})
+
+	if loc := UserLocation("u1"); loc != nil {
+		t.Errorf("Expected no timezone before one is set, got %v", loc)
+	}
+	if err := SetTimezone("u1", "Not/AZone"); err == nil {
+		t.Error("Expected an error for an unknown timezone")
+	}
+	if err := SetTimezone("u1", "UTC"); err != nil {
+		t.Fatal(err)
+	}
+
+	settings = newJSONStore[UserSettings]("settings.json")
+	if err := LoadSettings(dir); err != nil {
+		t.Fatal(err)
+	}
+	if loc := UserLocation("u1"); loc == nil || loc.String() != "UTC" {
+		t.Errorf("Expected UTC after reload, got %v", loc)
+	}
+
+	if err := SetTimezone("u1", ""); err != nil {
+		t.Fatal(err)
+	}
+	if loc := UserLocation("u1"); loc != nil {
+		t.Errorf("Expected no timezone after clearing it, got %v", loc)
+	}
+	data, err := os.ReadFile(dir + "/settings.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "{}" {
+		t.Errorf("Expected an empty settings.json, got %s", data)
+	}
+}
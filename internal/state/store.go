@@ -0,0 +1,78 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// jsonStore is per-key state kept in memory and, once loaded, persisted as
+// one JSON file in the data directory. Zero values are dropped.
+type jsonStore[V comparable] struct {
+	mu     sync.Mutex
+	file   string
+	path   string
+	values map[string]V
+}
+
+func newJSONStore[V comparable](file string) *jsonStore[V] {
+	return &jsonStore[V]{file: file, values: map[string]V{}}
+}
+
+// load reads the file from dataDir and persists later changes there.
+func (st *jsonStore[V]) load(dataDir string) error {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return fmt.Errorf("create directory %s: %w", dataDir, err)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.path = filepath.Join(dataDir, st.file)
+	data, err := os.ReadFile(st.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	loaded := map[string]V{}
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return fmt.Errorf("parse %s: %w", st.path, err)
+	}
+	st.values = loaded
+	return nil
+}
+
+func (st *jsonStore[V]) get(key string) V {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.values[key]
+}
+
+// update changes the value of key and writes the file.
+func (st *jsonStore[V]) update(key string, fn func(*V)) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	v := st.values[key]
+	fn(&v)
+	var zero V
+	if v == zero {
+		delete(st.values, key)
+	} else {
+		st.values[key] = v
+	}
+
+	if st.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(st.values, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(st.path, data, 0644)
+}